@@ -0,0 +1,224 @@
+package iterator
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// ToChannelBatched is like ToChannel, but sends the items in slices of up to batch items to reduce
+// the synchronization overhead per item. The last slice may hold fewer items.
+//
+// A valid context should be passed that cancels when the iterator chain goes out of scope, this
+// prevents the goroutine from leaking if the channel is not fully consumed.
+//
+// ToChannelBatched panics if batch is not positive.
+func ToChannelBatched[T any](ctx context.Context, from Iterator[T], batch int) <-chan []T {
+	if batch <= 0 {
+		panic("ToChannelBatched: batch must be positive")
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		for {
+			items := ToSliceCap(Take(from, batch), batch)
+			if len(items) == 0 {
+				return
+			}
+			select {
+			case out <- items:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanOut spawns a goroutine that distributes the items from the specified iterator over n channels
+// in a round-robin fashion, so that every item is sent to exactly one of the channels. All channels
+// are closed once the iterator is exhausted.
+//
+// A slow consumer of one of the channels blocks the delivery to all others, so the channels should
+// be consumed concurrently.
+//
+// A valid context should be passed that cancels when the iterator chain goes out of scope, this
+// prevents the goroutine from leaking if the channels are not fully consumed.
+//
+// FanOut panics if n is not positive.
+func FanOut[T any](ctx context.Context, from Iterator[T], n int) []<-chan T {
+	if n <= 0 {
+		panic("FanOut: n must be positive")
+	}
+	chans := make([]chan T, n)
+	outs := make([]<-chan T, n)
+	for i := range chans {
+		chans[i] = make(chan T)
+		outs[i] = chans[i]
+	}
+	go func() {
+		defer func() {
+			for _, ch := range chans {
+				close(ch)
+			}
+		}()
+		i := 0
+		for item, ok := from.Next(); ok; item, ok = from.Next() {
+			select {
+			case chans[i] <- item:
+			case <-ctx.Done():
+				return
+			}
+			i = (i + 1) % n
+		}
+	}()
+	return outs
+}
+
+// Broadcast spawns a goroutine that sends every item from the specified iterator to each of the n
+// returned channels. All channels are closed together once the iterator is exhausted.
+//
+// An item is only pulled from the iterator once the previous item has been received from all
+// channels, so the slowest consumer determines the pace and the channels should be consumed
+// concurrently.
+//
+// A valid context should be passed that cancels when the iterator chain goes out of scope, this
+// prevents the goroutine from leaking if the channels are not fully consumed.
+//
+// Broadcast panics if n is not positive.
+func Broadcast[T any](ctx context.Context, from Iterator[T], n int) []<-chan T {
+	if n <= 0 {
+		panic("Broadcast: n must be positive")
+	}
+	chans := make([]chan T, n)
+	outs := make([]<-chan T, n)
+	for i := range chans {
+		chans[i] = make(chan T)
+		outs[i] = chans[i]
+	}
+	go func() {
+		defer func() {
+			for _, ch := range chans {
+				close(ch)
+			}
+		}()
+		for item, ok := from.Next(); ok; item, ok = from.Next() {
+			for _, ch := range chans {
+				select {
+				case ch <- item:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return outs
+}
+
+// ForEachParallel calls fn for every item from the specified iterator using the specified number
+// of concurrent workers. The context passed to fn is cancelled as soon as any call returns an
+// error, after which no more items are processed. ForEachParallel waits for all workers and the
+// goroutine pulling from the iterator to finish and returns the first error.
+//
+// If the specified context is cancelled before all items were processed and no call returned an
+// error, the error of the context is returned.
+//
+// ForEachParallel panics if workers is not positive.
+func ForEachParallel[T any](ctx context.Context, from Iterator[T], workers int, fn func(context.Context, T) error) error {
+	if workers <= 0 {
+		panic("ForEachParallel: workers must be positive")
+	}
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The items are counted so a cancellation after the last item was processed is not reported.
+	items := make(chan T)
+	done := make(chan struct{})
+	sent, exhausted := 0, false
+	go func() {
+		defer close(done)
+		defer close(items)
+		for item, ok := from.Next(); ok; item, ok = from.Next() {
+			select {
+			case items <- item:
+				sent++
+			case <-workCtx.Done():
+				return
+			}
+		}
+		exhausted = true
+	}()
+
+	var once sync.Once
+	var firstErr error
+	var processed int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				if workCtx.Err() != nil {
+					return
+				}
+				if err := fn(workCtx, item); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+				atomic.AddInt64(&processed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	// Workers may stop early after the context is cancelled, so the producer must be waited for
+	// separately before its counters can be read.
+	<-done
+	if firstErr != nil {
+		return firstErr
+	}
+	if exhausted && int(processed) == sent {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// MergeChannels returns an iterator over the items received from any of the specified channels, in
+// the order in which they arrive. The iterator is exhausted once all channels are closed or the
+// context is done.
+//
+// A goroutine is spawned for every channel. A valid context should be passed that cancels when the
+// iterator chain goes out of scope, this prevents the goroutines from leaking if the iterator is
+// not fully consumed.
+func MergeChannels[T any](ctx context.Context, chans ...<-chan T) Iterator[T] {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				select {
+				case item, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case out <- item:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return FromChannelContext(ctx, out)
+}
@@ -0,0 +1,201 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestToChannelBatched(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		ch := ToChannelBatched(context.Background(), Range(0, 10, 1), 4)
+		batches := ToSlice(FromChannel(ch))
+		if !reflect.DeepEqual(batches, [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}}) {
+			t.Fatalf("Unexpected: %v", batches)
+		}
+	})
+	t.Run("cancel unconsumed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := ToChannelBatched(ctx, Repeat(1), 4)
+		<-ch
+		cancel()
+		// The source is infinite, so this only terminates if the goroutine stops.
+		Drain(FromChannel(ch))
+	})
+}
+
+func TestFanOut(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		outs := FanOut(context.Background(), Range(0, 100, 1), 3)
+		results := make([][]int, len(outs))
+		var wg sync.WaitGroup
+		for i, out := range outs {
+			wg.Add(1)
+			go func(i int, out <-chan int) {
+				defer wg.Done()
+				results[i] = ToSlice(FromChannel(out))
+			}(i, out)
+		}
+		wg.Wait()
+
+		all := ToSlice(Flatten(Map(FromSlice(results), FromSlice[int])))
+		sort.Ints(all)
+		if !reflect.DeepEqual(all, ToSlice(Range(0, 100, 1))) {
+			t.Fatalf("Unexpected: %v", all)
+		}
+	})
+	t.Run("cancel unconsumed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		outs := FanOut(ctx, Repeat(1), 2)
+		<-outs[0]
+		cancel()
+		// The source is infinite, so this only terminates if the goroutine stops.
+		for _, out := range outs {
+			Drain(FromChannel(out))
+		}
+	})
+}
+
+func TestBroadcast(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		outs := Broadcast(context.Background(), Range(0, 100, 1), 3)
+		results := make([][]int, len(outs))
+		var wg sync.WaitGroup
+		for i, out := range outs {
+			wg.Add(1)
+			go func(i int, out <-chan int) {
+				defer wg.Done()
+				results[i] = ToSlice(FromChannel(out))
+			}(i, out)
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			if !reflect.DeepEqual(result, ToSlice(Range(0, 100, 1))) {
+				t.Fatalf("Unexpected: %v", result)
+			}
+		}
+	})
+	t.Run("cancel unconsumed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		outs := Broadcast(ctx, Repeat(1), 2)
+		<-outs[0]
+		cancel()
+		// The source is infinite, so this only terminates if the goroutine stops.
+		for _, out := range outs {
+			Drain(FromChannel(out))
+		}
+	})
+}
+
+func TestForEachParallel(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		var lock sync.Mutex
+		seen := []int{}
+		err := ForEachParallel(context.Background(), Range(0, 100, 1), 4, func(_ context.Context, i int) error {
+			lock.Lock()
+			defer lock.Unlock()
+			seen = append(seen, i)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		sort.Ints(seen)
+		if !reflect.DeepEqual(seen, ToSlice(Range(0, 100, 1))) {
+			t.Fatalf("Unexpected: %v", seen)
+		}
+	})
+	t.Run("error cancels others", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		err := ForEachParallel(context.Background(), Range(0, 100, 1), 3, func(ctx context.Context, i int) error {
+			if i == 0 {
+				return errBoom
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		if err != errBoom {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := ForEachParallel(ctx, Range(0, 100, 1), 2, func(_ context.Context, i int) error {
+			if i == 0 {
+				cancel()
+			}
+			return nil
+		})
+		if err != context.Canceled {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("cancelled with items left", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := ForEachParallel(ctx, Range(0, 3, 1), 1, func(_ context.Context, i int) error {
+			if i == 1 {
+				cancel()
+			}
+			return nil
+		})
+		if err != context.Canceled {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("cancelled after last item", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var lock sync.Mutex
+		count := 0
+		err := ForEachParallel(ctx, Range(0, 100, 1), 4, func(_ context.Context, i int) error {
+			lock.Lock()
+			defer lock.Unlock()
+			if count++; count == 100 {
+				cancel()
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestMergeChannels(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		ctx := context.Background()
+		a := ToChannel(ctx, Range(0, 50, 1), 0)
+		b := ToChannel(ctx, Range(50, 100, 1), 0)
+		result := ToSlice(MergeChannels(ctx, a, b))
+		sort.Ints(result)
+		if !reflect.DeepEqual(result, ToSlice(Range(0, 100, 1))) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("cancel unconsumed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		iter := MergeChannels(ctx, make(chan int), ToChannel(ctx, Repeat(1), 0))
+		iter.Next()
+		cancel()
+		// One of the sources is infinite, so this only terminates if the iterator stops.
+		Drain(iter)
+	})
+	t.Run("cancel buffered", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 10)
+		for i := 0; i < 10; i++ {
+			ch <- i
+		}
+		cancel()
+		iter := MergeChannels(ctx, ch)
+		if val, ok := iter.Next(); ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package iterator
+
+// A Collector describes a reusable aggregation of items into a result.
+//
+// Accumulation starts at the zero value of O. Each item is folded into the result with Accumulate
+// after which Finish is applied once to produce the final result.
+type Collector[T any, O any] interface {
+	Accumulate(accum O, item T) O
+	Finish(accum O) O
+}
+
+// Collect consumes the entire iterator and aggregates the items using the specified collector.
+func Collect[T any, O any](from Iterator[T], c Collector[T, O]) O {
+	var zero O
+	return c.Finish(Reduce(from, c.Accumulate, zero))
+}
+
+// SumCollector returns a collector that adds all items.
+func SumCollector[T Number]() Collector[T, T] {
+	return sumCollector[T]{}
+}
+
+type sumCollector[T Number] struct{}
+
+func (sumCollector[T]) Accumulate(accum T, item T) T { return accum + item }
+func (sumCollector[T]) Finish(accum T) T             { return accum }
+
+// CountCollector returns a collector that counts the items.
+func CountCollector[T any]() Collector[T, int] {
+	return countCollector[T]{}
+}
+
+type countCollector[T any] struct{}
+
+func (countCollector[T]) Accumulate(accum int, _ T) int { return accum + 1 }
+func (countCollector[T]) Finish(accum int) int          { return accum }
+
+// SliceCollector returns a collector that collects the items into a slice.
+func SliceCollector[T any]() Collector[T, []T] {
+	return sliceCollector[T]{}
+}
+
+type sliceCollector[T any] struct{}
+
+func (sliceCollector[T]) Accumulate(accum []T, item T) []T { return append(accum, item) }
+
+func (sliceCollector[T]) Finish(accum []T) []T {
+	if accum == nil {
+		return []T{}
+	}
+	return accum
+}
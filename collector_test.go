@@ -0,0 +1,55 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	t.Run("sum", func(t *testing.T) {
+		result := Collect(Range(1, 5, 1), SumCollector[int]())
+		if result != 10 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("count", func(t *testing.T) {
+		result := Collect(Range(1, 5, 1), CountCollector[int]())
+		if result != 4 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("slice", func(t *testing.T) {
+		result := Collect(Range(1, 5, 1), SliceCollector[int]())
+		if !reflect.DeepEqual(result, []int{1, 2, 3, 4}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if result := Collect(Empty[int](), SliceCollector[int]()); !reflect.DeepEqual(result, []int{}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("custom", func(t *testing.T) {
+		result := Collect[int, average](Range(1, 5, 1), averageCollector{})
+		if result.mean != 2.5 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+type average struct {
+	sum   int
+	count int
+	mean  float64
+}
+
+type averageCollector struct{}
+
+func (averageCollector) Accumulate(accum average, item int) average {
+	return average{sum: accum.sum + item, count: accum.count + 1}
+}
+
+func (averageCollector) Finish(accum average) average {
+	if accum.count > 0 {
+		accum.mean = float64(accum.sum) / float64(accum.count)
+	}
+	return accum
+}
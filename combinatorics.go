@@ -0,0 +1,155 @@
+package iterator
+
+// CartesianProduct returns an iterator over all pairs of items from a and b, in row-major order.
+//
+// Because b has to be replayed for each item of a, all items of b are buffered on first use.
+func CartesianProduct[A any, B any](a Iterator[A], b Iterator[B]) Iterator[Pair[A, B]] {
+	return &cartesianProductIterator[A, B]{a: a, b: b}
+}
+
+type cartesianProductIterator[A any, B any] struct {
+	a        Iterator[A]
+	b        Iterator[B]
+	bItems   []B
+	bIndex   int
+	head     A
+	haveHead bool
+}
+
+func (iter *cartesianProductIterator[A, B]) buffer() {
+	if iter.b != nil {
+		iter.bItems = ToSlice(iter.b)
+		iter.b = nil
+	}
+}
+
+func (iter *cartesianProductIterator[A, B]) Next() (Pair[A, B], bool) {
+	iter.buffer()
+	if len(iter.bItems) == 0 {
+		var zero Pair[A, B]
+		return zero, false
+	}
+	if !iter.haveHead || iter.bIndex >= len(iter.bItems) {
+		head, ok := iter.a.Next()
+		if !ok {
+			iter.haveHead = false
+			var zero Pair[A, B]
+			return zero, false
+		}
+		iter.head, iter.haveHead, iter.bIndex = head, true, 0
+	}
+	item := Pair[A, B]{A: iter.head, B: iter.bItems[iter.bIndex]}
+	iter.bIndex++
+	return item, true
+}
+
+func (iter *cartesianProductIterator[A, B]) Count() int {
+	iter.buffer()
+	if len(iter.bItems) == 0 {
+		// Like Next, do not touch a as it may be infinite.
+		return 0
+	}
+	count := 0
+	if iter.haveHead {
+		count = len(iter.bItems) - iter.bIndex
+		iter.haveHead = false
+	}
+	count += Count(iter.a) * len(iter.bItems)
+	return count
+}
+
+// Combinations returns an iterator over all k-sized combinations of the specified items in
+// lexicographic order of their indices. Each combination is returned as a new slice.
+//
+// If k is larger than the number of items, nothing is returned. If k is 0, a single empty
+// combination is returned.
+//
+// Combinations panics if k is negative.
+func Combinations[T any](items []T, k int) Iterator[[]T] {
+	if k < 0 {
+		panic("Combinations: k may not be negative")
+	}
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	return &combinationsIterator[T]{items: items, indices: indices, done: k > len(items)}
+}
+
+type combinationsIterator[T any] struct {
+	items   []T
+	indices []int
+	done    bool
+}
+
+func (iter *combinationsIterator[T]) Next() ([]T, bool) {
+	if iter.done {
+		return nil, false
+	}
+	combination := make([]T, len(iter.indices))
+	for i, index := range iter.indices {
+		combination[i] = iter.items[index]
+	}
+
+	// Advance to the next combination by incrementing the rightmost index that has not yet reached
+	// its maximum value and resetting all indices to the right of it.
+	n, k := len(iter.items), len(iter.indices)
+	i := k - 1
+	for i >= 0 && iter.indices[i] == n-k+i {
+		i--
+	}
+	if i < 0 {
+		iter.done = true
+	} else {
+		iter.indices[i]++
+		for j := i + 1; j < k; j++ {
+			iter.indices[j] = iter.indices[j-1] + 1
+		}
+	}
+	return combination, true
+}
+
+// Permutations returns an iterator over all orderings of the specified items. Each permutation is
+// returned as a new slice. An empty slice of items results in a single empty permutation.
+//
+// Keep in mind that n items have n! permutations, so the number of returned slices grows very
+// quickly.
+func Permutations[T any](items []T) Iterator[[]T] {
+	return &permutationsIterator[T]{
+		items: append([]T{}, items...),
+		state: make([]int, len(items)),
+		first: true,
+	}
+}
+
+// permutationsIterator implements the iterative form of Heap's algorithm, which generates each
+// permutation from the previous one by a single swap.
+type permutationsIterator[T any] struct {
+	items []T
+	state []int
+	index int
+	first bool
+}
+
+func (iter *permutationsIterator[T]) Next() ([]T, bool) {
+	if iter.first {
+		iter.first = false
+		return append([]T{}, iter.items...), true
+	}
+	for iter.index < len(iter.items) {
+		if iter.state[iter.index] < iter.index {
+			if iter.index%2 == 0 {
+				iter.items[0], iter.items[iter.index] = iter.items[iter.index], iter.items[0]
+			} else {
+				j := iter.state[iter.index]
+				iter.items[j], iter.items[iter.index] = iter.items[iter.index], iter.items[j]
+			}
+			iter.state[iter.index]++
+			iter.index = 0
+			return append([]T{}, iter.items...), true
+		}
+		iter.state[iter.index] = 0
+		iter.index++
+	}
+	return nil, false
+}
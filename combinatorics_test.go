@@ -0,0 +1,86 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCartesianProduct(t *testing.T) {
+	iter := CartesianProduct(FromSlice([]int{1, 2}), FromSlice([]string{"x", "y"}))
+	result := ToSlice(iter)
+	expect := []Pair[int, string]{
+		{A: 1, B: "x"},
+		{A: 1, B: "y"},
+		{A: 2, B: "x"},
+		{A: 2, B: "y"},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, CartesianProduct(Range(0, 3, 1), Range(0, 4, 1)), 12)
+
+	countIter := CartesianProduct(Range(0, 3, 1), Range(0, 4, 1))
+	countIter.Next() // Test whether the partially consumed row is included.
+	testCounterImplementation(t, countIter, 11)
+	testCounterImplementation(t, CartesianProduct(Repeat(1), Empty[int]()), 0)
+}
+
+func TestCombinations(t *testing.T) {
+	t.Run("choose 2 of 4", func(t *testing.T) {
+		iter := Combinations([]int{1, 2, 3, 4}, 2)
+		result := ToSlice(iter)
+		expect := [][]int{{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}}
+		if !reflect.DeepEqual(result, expect) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("choose 0", func(t *testing.T) {
+		iter := Combinations([]int{1, 2, 3}, 0)
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, [][]int{{}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("choose too many", func(t *testing.T) {
+		iter := Combinations([]int{1, 2, 3}, 4)
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, [][]int{}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("panic on negative k", func(t *testing.T) {
+		var err interface{}
+		func() {
+			defer func() { err = recover() }()
+			Combinations([]int{1, 2, 3}, -1)
+		}()
+		if err == nil {
+			t.Fatalf("Expected panic")
+		}
+	})
+}
+
+func TestPermutations(t *testing.T) {
+	t.Run("three items", func(t *testing.T) {
+		iter := Permutations([]int{1, 2, 3})
+		result := ToSlice(iter)
+		if len(result) != 6 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		seen := map[[3]int]bool{}
+		for _, perm := range result {
+			seen[[3]int{perm[0], perm[1], perm[2]}] = true
+		}
+		if len(seen) != 6 {
+			t.Fatalf("Unexpected duplicates: %v", result)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		iter := Permutations([]int{})
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, [][]int{{}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
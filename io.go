@@ -0,0 +1,76 @@
+package iterator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// A CSVOption configures the csv.Reader used by FromCSV.
+type CSVOption func(*csv.Reader)
+
+// WithCSVComma sets the field delimiter used by FromCSV.
+func WithCSVComma(comma rune) CSVOption {
+	return func(r *csv.Reader) {
+		r.Comma = comma
+	}
+}
+
+// FromCSV creates a new iterator which lazily returns the records read from the CSV encoded reader.
+//
+// Iteration stops at the first error, which can then be retrieved using Err.
+func FromCSV(r io.Reader, opts ...CSVOption) Fallible[[]string] {
+	reader := csv.NewReader(r)
+	for _, opt := range opts {
+		opt(reader)
+	}
+	return &csvIterator{reader: reader}
+}
+
+type csvIterator struct {
+	reader *csv.Reader
+	err    error
+}
+
+func (iter *csvIterator) Next() ([]string, bool) {
+	if iter.reader == nil {
+		return nil, false
+	}
+	record, err := iter.reader.Read()
+	if err != nil {
+		if err != io.EOF {
+			iter.err = err
+		}
+		iter.reader = nil
+		return nil, false
+	}
+	return record, true
+}
+
+func (iter *csvIterator) Err() error {
+	return iter.err
+}
+
+// CountLines counts the newline-delimited lines read from the reader without retaining them. A
+// final line that is not terminated by a newline is counted as well.
+func CountLines(r io.Reader) (int, error) {
+	buf := make([]byte, 32*1024)
+	count := 0
+	var last byte = '\n'
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			count += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return count, err
+		}
+	}
+	if last != '\n' {
+		count++
+	}
+	return count, nil
+}
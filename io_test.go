@@ -0,0 +1,64 @@
+package iterator
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFromCSV(t *testing.T) {
+	t.Run("records", func(t *testing.T) {
+		iter := FromCSV(strings.NewReader("a,b\n\"c,d\",e\n"))
+		result := ToSlice[[]string](iter)
+		if !reflect.DeepEqual(result, [][]string{{"a", "b"}, {"c,d", "e"}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if err := iter.Err(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("comma", func(t *testing.T) {
+		iter := FromCSV(strings.NewReader("a;b\nc;d\n"), WithCSVComma(';'))
+		result := ToSlice[[]string](iter)
+		if !reflect.DeepEqual(result, [][]string{{"a", "b"}, {"c", "d"}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("malformed", func(t *testing.T) {
+		iter := FromCSV(strings.NewReader("a,b\nc,d,e\nf,g\n"))
+		result := ToSlice[[]string](iter)
+		if !reflect.DeepEqual(result, [][]string{{"a", "b"}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if err := iter.Err(); !errors.Is(err, csv.ErrFieldCount) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestCountLines(t *testing.T) {
+	for input, expect := range map[string]int{
+		"":         0,
+		"a":        1,
+		"a\nb":     2,
+		"a\nb\n":   2,
+		"\n\n":     2,
+		"a\n\nb\n": 3,
+	} {
+		count, err := CountLines(strings.NewReader(input))
+		if err != nil || count != expect {
+			t.Fatalf("Unexpected for %q: %v, %v", input, count, err)
+		}
+	}
+	t.Run("error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		_, err := CountLines(io.MultiReader(strings.NewReader("a\n"), iotest.ErrReader(errBoom)))
+		if err != errBoom {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
@@ -2,6 +2,10 @@ package iterator
 
 import (
 	"context"
+	"math"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
 )
 
 // An Iterator is a stream of items of some type.
@@ -17,6 +21,39 @@ type Iterator[T any] interface {
 	Next() (T, bool)
 }
 
+// Fallible is implemented by iterators that may stop because of an error, such as iterators backed
+// by I/O. After Next has returned false, Err reports the error that stopped the iteration, or nil if
+// the iterator was exhausted normally.
+type Fallible[T any] interface {
+	Iterator[T]
+	Err() error
+}
+
+// Seekable can optionally be implemented by iterators over random-access sources. Seek moves the
+// iterator such that the next call to Next returns the item at the specified zero-based index of
+// the source, regardless of how many items have been consumed before. Seeking beyond the end
+// exhausts the iterator, seeking to a negative index panics.
+type Seekable[T any] interface {
+	Iterator[T]
+	Seek(index int)
+}
+
+// Cloneable can optionally be implemented by iterators of which the state can be copied cheaply.
+// Clone returns an independent iterator that continues at the same position.
+type Cloneable[T any] interface {
+	Iterator[T]
+	Clone() Iterator[T]
+}
+
+// Clone returns an independent copy of the specified iterator if it implements Cloneable. False is
+// returned otherwise.
+func Clone[T any](from Iterator[T]) (Iterator[T], bool) {
+	if cloneable, ok := from.(Cloneable[T]); ok {
+		return cloneable.Clone(), true
+	}
+	return nil, false
+}
+
 // Empty returns an iterator that never returns anything.
 func Empty[T any]() Iterator[T] {
 	return emptyIterator[T]{}
@@ -33,31 +70,43 @@ func (emptyIterator[T]) Count() int {
 	return 0
 }
 
+func (iter emptyIterator[T]) Clone() Iterator[T] {
+	return iter
+}
+
 // Once returns an iterator that returns the specified item only once.
 func Once[T any](item T) Iterator[T] {
-	return &onceIterator[T]{item: &item}
+	return &onceIterator[T]{item: item}
 }
 
 type onceIterator[T any] struct {
-	item *T
+	item T
+	done bool
 }
 
 func (iter *onceIterator[T]) Next() (T, bool) {
-	if iter.item != nil {
-		item := *iter.item
-		iter.item = nil
-		return item, true
+	if iter.done {
+		var zero T
+		return zero, false
 	}
+	item := iter.item
 	var zero T
-	return zero, false
+	iter.item, iter.done = zero, true
+	return item, true
 }
 
 func (iter *onceIterator[T]) Count() int {
-	if iter.item != nil {
-		iter.item = nil
-		return 1
+	if iter.done {
+		return 0
 	}
-	return 0
+	var zero T
+	iter.item, iter.done = zero, true
+	return 1
+}
+
+func (iter *onceIterator[T]) Clone() Iterator[T] {
+	clone := *iter
+	return &clone
 }
 
 // Repeat returns an iterator that returns copies of the specified item indefinitely.
@@ -73,6 +122,24 @@ func (iter *repeatIterator[T]) Next() (T, bool) {
 	return iter.item, true
 }
 
+func (iter *repeatIterator[T]) Clone() Iterator[T] {
+	clone := *iter
+	return &clone
+}
+
+// RepeatWith returns an iterator that calls the specified function for each item indefinitely.
+func RepeatWith[T any](gen func() T) Iterator[T] {
+	return &repeatWithIterator[T]{gen: gen}
+}
+
+type repeatWithIterator[T any] struct {
+	gen func() T
+}
+
+func (iter *repeatWithIterator[T]) Next() (T, bool) {
+	return iter.gen(), true
+}
+
 // Range creates an iterator which returns the numeric range between start inclusive and end
 // exclusive by the step size.
 //
@@ -108,32 +175,71 @@ func (iter *rangeIterator[T]) Count() int {
 	return int(count)
 }
 
+func (iter *rangeIterator[T]) Clone() Iterator[T] {
+	clone := *iter
+	return &clone
+}
+
+// Ints returns an iterator over the integers 0 up to but not including n. A non-positive n results
+// in an empty iterator.
+func Ints(n int) Iterator[int] {
+	return IntsFrom(0, n)
+}
+
+// IntsFrom returns an iterator over the n consecutive integers starting at start. A non-positive n
+// results in an empty iterator. Rather than overflowing, the iterator stops before math.MaxInt.
+func IntsFrom(start, n int) Iterator[int] {
+	if n < 0 {
+		n = 0
+	} else if start > 0 && n > math.MaxInt-start {
+		n = math.MaxInt - start
+	}
+	return Range(start, start+n, 1)
+}
+
 // FromSlice creates a new iterator which returns all items from the slice starting at index 0 until
 // all items are consumed.
+//
+// The returned iterator implements Seekable.
 func FromSlice[T any](slice []T) Iterator[T] {
 	return &sliceIterator[T]{slice: slice}
 }
 
 type sliceIterator[T any] struct {
 	slice []T
+	index int
 }
 
 func (iter *sliceIterator[T]) Next() (T, bool) {
-	if len(iter.slice) == 0 {
+	if iter.index >= len(iter.slice) {
 		var zero T
 		return zero, false
 	}
-	item := iter.slice[0]
-	iter.slice = iter.slice[1:]
+	item := iter.slice[iter.index]
+	iter.index++
 	return item, true
 }
 
 func (iter *sliceIterator[T]) Count() int {
-	count := len(iter.slice)
-	iter.slice = []T{}
+	count := len(iter.slice) - iter.index
+	iter.index = len(iter.slice)
 	return count
 }
 
+func (iter *sliceIterator[T]) Seek(index int) {
+	if index < 0 {
+		panic("Seek: index may not be negative")
+	} else if index > len(iter.slice) {
+		index = len(iter.slice)
+	}
+	iter.index = index
+}
+
+func (iter *sliceIterator[T]) Clone() Iterator[T] {
+	clone := *iter
+	return &clone
+}
+
 // ToSlice collects the items from the specified iterator into a slice.
 func ToSlice[T any](from Iterator[T]) []T {
 	slice := []T{}
@@ -143,6 +249,26 @@ func ToSlice[T any](from Iterator[T]) []T {
 	return slice
 }
 
+// ToSliceCap is like ToSlice, but allocates the slice with the specified capacity up front. This
+// avoids reallocations when the approximate number of items is known beforehand. The slice still
+// grows if the iterator returns more items.
+func ToSliceCap[T any](from Iterator[T], capacity int) []T {
+	slice := make([]T, 0, capacity)
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		slice = append(slice, item)
+	}
+	return slice
+}
+
+// AppendTo appends the items from the specified iterator to dst and returns the extended slice,
+// like the built-in append.
+func AppendTo[T any](dst []T, from Iterator[T]) []T {
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		dst = append(dst, item)
+	}
+	return dst
+}
+
 func FromChannel[T any](from <-chan T) Iterator[T] {
 	return &channelIterator[T]{from: from}
 }
@@ -156,6 +282,33 @@ func (iter *channelIterator[T]) Next() (T, bool) {
 	return item, ok
 }
 
+// FromChannelContext is like FromChannel, but Next also returns false once the context is done,
+// even if the channel is not closed.
+func FromChannelContext[T any](ctx context.Context, from <-chan T) Iterator[T] {
+	return &channelContextIterator[T]{ctx: ctx, from: from}
+}
+
+type channelContextIterator[T any] struct {
+	ctx  context.Context
+	from <-chan T
+}
+
+func (iter *channelContextIterator[T]) Next() (T, bool) {
+	// Select picks at random if both cases are ready, so check the context first to not keep
+	// returning buffered items after it is done.
+	if iter.ctx.Err() != nil {
+		var zero T
+		return zero, false
+	}
+	select {
+	case item, ok := <-iter.from:
+		return item, ok
+	case <-iter.ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
 // ToChannel spawns a new goroutine that pulls from the specified iterator into the returned
 // channel. The channel may be buffered, which causes the preceding iterator chain to run in
 // parallel to the routine that consumes from the channel.
@@ -177,6 +330,40 @@ func ToChannel[T any](ctx context.Context, from Iterator[T], buffer int) <-chan
 	return out
 }
 
+// ToChannelErr is like ToChannel, but additionally returns a channel over which the error that
+// stopped the iteration is delivered. The error is taken from Err if the source implements
+// Fallible, or is the context's error if the context was cancelled. At most one error is sent, after
+// the data channel has been closed. The error channel is closed afterwards, without sending
+// anything if the iteration completed normally.
+//
+// A valid context should be passed that cancels when the iterator chain goes out of scope, this
+// prevents the goroutine from leaking if the channel is not fully consumed.
+func ToChannelErr[T any](ctx context.Context, from Iterator[T]) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		err := func() error {
+			defer close(out)
+			for item, ok := from.Next(); ok; item, ok = from.Next() {
+				select {
+				case out <- item:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if f, ok := from.(Fallible[T]); ok {
+				return f.Err()
+			}
+			return nil
+		}()
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return out, errs
+}
+
 // Go is a convenience function that calls ToChannel and then FromChannel with a buffer size of 1.
 //
 // The effect of this is that the iterator chain preceding this call runs in parallel to
@@ -188,6 +375,36 @@ func Go[T any](ctx context.Context, from Iterator[T]) Iterator[T] {
 	return FromChannel(ToChannel(ctx, from, 1))
 }
 
+// Buffer runs the specified iterator in a separate goroutine that keeps up to size items ready to
+// be consumed. This smooths out sources that produce items in bursts.
+//
+// A valid context should be passed that cancels when the iterator chain goes out of scope, this
+// prevents the goroutine from leaking if the iterator chain is not fully consumed.
+func Buffer[T any](ctx context.Context, from Iterator[T], size int) Iterator[T] {
+	return FromChannel(ToChannel(ctx, from, size))
+}
+
+// WithContext returns an iterator that returns the items from the specified iterator until the
+// context is done. The context is checked before each item is pulled from the source.
+//
+// Unlike Go, no goroutine is spawned. So a Next call that blocks in the source is not interrupted.
+func WithContext[T any](ctx context.Context, from Iterator[T]) Iterator[T] {
+	return &contextIterator[T]{ctx: ctx, from: from}
+}
+
+type contextIterator[T any] struct {
+	ctx  context.Context
+	from Iterator[T]
+}
+
+func (iter *contextIterator[T]) Next() (T, bool) {
+	if iter.ctx.Err() != nil {
+		var zero T
+		return zero, false
+	}
+	return iter.from.Next()
+}
+
 // FromMap creates a new iterator that traverses through all the entries of the map.
 //
 // The order in which entries are returned is non-deterministic, just like regular Go map iteration.
@@ -207,6 +424,37 @@ func FromMap[K comparable, V any](from map[K]V) Iterator[MapEntry[K, V]] {
 	return FromSlice(entries)
 }
 
+// FromMapSorted is like FromMap, but returns the entries in ascending order of their keys.
+func FromMapSorted[K constraints.Ordered, V any](from map[K]V) Iterator[MapEntry[K, V]] {
+	entries := ToSlice(FromMap(from))
+	slices.SortFunc(entries, func(a, b MapEntry[K, V]) bool {
+		return a.Key < b.Key
+	})
+	return FromSlice(entries)
+}
+
+// FromMapKeys creates a new iterator that traverses through all the keys of the map.
+//
+// The order in which keys are returned is non-deterministic, just like regular Go map iteration.
+func FromMapKeys[K comparable, V any](from map[K]V) Iterator[K] {
+	keys := make([]K, 0, len(from))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	return FromSlice(keys)
+}
+
+// FromMapValues creates a new iterator that traverses through all the values of the map.
+//
+// The order in which values are returned is non-deterministic, just like regular Go map iteration.
+func FromMapValues[K comparable, V any](from map[K]V) Iterator[V] {
+	vals := make([]V, 0, len(from))
+	for _, v := range from {
+		vals = append(vals, v)
+	}
+	return FromSlice(vals)
+}
+
 // ToMap builds a map from an iterator over MapEntry items.
 //
 // Duplicate keys are silently overwritten, giving precedence to the last item from the iterator.
@@ -218,7 +466,55 @@ func ToMap[K comparable, V any](from Iterator[MapEntry[K, V]]) map[K]V {
 	return out
 }
 
+// ToMapGroup builds a map from an iterator over MapEntry items, collecting the values of duplicate
+// keys into a slice in the order in which they were returned.
+func ToMapGroup[K comparable, V any](from Iterator[MapEntry[K, V]]) map[K][]V {
+	out := map[K][]V{}
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		out[item.Key] = append(out[item.Key], item.Val)
+	}
+	return out
+}
+
+// Index builds a map from the items of an iterator keyed by the result of the specified key
+// function.
+//
+// Items with duplicate keys are silently overwritten, giving precedence to the last item from the
+// iterator.
+func Index[T any, K comparable](from Iterator[T], keyFunc func(T) K) map[K]T {
+	out := map[K]T{}
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		out[keyFunc(item)] = item
+	}
+	return out
+}
+
+// ToSet collects the distinct items from the specified iterator into a map with empty values.
+func ToSet[T comparable](from Iterator[T]) map[T]struct{} {
+	out := map[T]struct{}{}
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		out[item] = struct{}{}
+	}
+	return out
+}
+
 type MapEntry[K comparable, V any] struct {
 	Key K
 	Val V
 }
+
+// Pair holds two values of possibly different types.
+type Pair[A any, B any] struct {
+	A A
+	B B
+}
+
+// Unzip collects the pairs from the specified iterator into two slices of equal length.
+func Unzip[A any, B any](from Iterator[Pair[A, B]]) ([]A, []B) {
+	as, bs := []A{}, []B{}
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		as = append(as, item.A)
+		bs = append(bs, item.B)
+	}
+	return as, bs
+}
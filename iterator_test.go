@@ -2,9 +2,14 @@ package iterator
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
+	"math"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/exp/constraints"
 )
@@ -42,6 +47,18 @@ func TestRepeat(t *testing.T) {
 	}
 }
 
+func TestRepeatWith(t *testing.T) {
+	counter := 0
+	iter := RepeatWith(func() int {
+		counter++
+		return counter
+	})
+	result := ToSlice(Take(iter, 3))
+	if !reflect.DeepEqual(result, []int{1, 2, 3}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
 func TestRange(t *testing.T) {
 	t.Run("count to 5", func(t *testing.T) {
 		iter := Range[int](0, 5, 1)
@@ -84,6 +101,24 @@ func TestRange(t *testing.T) {
 	testCounterImplementation(t, Range[int](0, 0, 1), 0)
 }
 
+func TestInts(t *testing.T) {
+	if result := ToSlice(Ints(5)); !reflect.DeepEqual(result, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if result := ToSlice(Ints(-1)); !reflect.DeepEqual(result, []int{}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if result := ToSlice(IntsFrom(3, 3)); !reflect.DeepEqual(result, []int{3, 4, 5}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if result := ToSlice(IntsFrom(math.MaxInt-1, 5)); !reflect.DeepEqual(result, []int{math.MaxInt - 1}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, Ints(5), 5)
+	testCounterImplementation(t, IntsFrom(3, 3), 3)
+}
+
 func TestFromSlice(t *testing.T) {
 	t.Run("items", func(t *testing.T) {
 		iter := FromSlice[int]([]int{1, 2, 3, 4})
@@ -104,8 +139,147 @@ func TestFromSlice(t *testing.T) {
 	testCounterImplementation(t, FromSlice[int]([]int{1, 2, 3, 4}), 4)
 }
 
+func TestSeekable(t *testing.T) {
+	iter, ok := FromSlice([]int{0, 1, 2, 3, 4}).(Seekable[int])
+	if !ok {
+		t.Fatalf("FromSlice does not implement Seekable")
+	}
+	iter.Next()
+	iter.Next()
+	iter.Seek(1)
+	if val, ok := iter.Next(); !ok || val != 1 {
+		t.Fatalf("Unexpected after seeking backward: %v, %v", val, ok)
+	}
+	iter.Seek(4)
+	if result := ToSlice[int](iter); !reflect.DeepEqual(result, []int{4}) {
+		t.Fatalf("Unexpected after seeking forward: %v", result)
+	}
+	iter.Seek(10)
+	if val, ok := iter.Next(); ok {
+		t.Fatalf("Unexpected after seeking beyond the end: %v", val)
+	}
+	iter.Seek(0)
+	if count := Count[int](iter); count != 5 {
+		t.Fatalf("Unexpected count after rewinding: %v", count)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Run("range", func(t *testing.T) {
+		iter := Range(0, 5, 1)
+		iter.Next()
+		iter.Next()
+		clone, ok := Clone(iter)
+		if !ok {
+			t.Fatalf("Range is not cloneable")
+		}
+		iter.Next()
+		if result := ToSlice(clone); !reflect.DeepEqual(result, []int{2, 3, 4}) {
+			t.Fatalf("Unexpected clone: %v", result)
+		}
+		if result := ToSlice(iter); !reflect.DeepEqual(result, []int{3, 4}) {
+			t.Fatalf("Unexpected original: %v", result)
+		}
+	})
+	t.Run("cloneable", func(t *testing.T) {
+		for _, iter := range []Iterator[int]{
+			Empty[int](),
+			Once(1),
+			Repeat(1),
+			Range(0, 5, 1),
+			FromSlice([]int{1, 2, 3}),
+		} {
+			if _, ok := Clone(iter); !ok {
+				t.Fatalf("%T is not cloneable", iter)
+			}
+		}
+	})
+	t.Run("not cloneable", func(t *testing.T) {
+		if _, ok := Clone(Map(Range(0, 5, 1), func(i int) int { return i })); ok {
+			t.Fatalf("Unexpected clone of Map")
+		}
+	})
+}
+
 // ToSlice is already quite well covered because it is used in other tests.
 
+func TestToSliceCap(t *testing.T) {
+	t.Run("exact", func(t *testing.T) {
+		result := ToSliceCap(Range(0, 4, 1), 4)
+		if !reflect.DeepEqual(result, []int{0, 1, 2, 3}) || cap(result) != 4 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("too low", func(t *testing.T) {
+		result := ToSliceCap(Range(0, 4, 1), 2)
+		if !reflect.DeepEqual(result, []int{0, 1, 2, 3}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestAppendTo(t *testing.T) {
+	dst := []int{10, 11}
+	result := AppendTo(dst, Range(0, 3, 1))
+	if !reflect.DeepEqual(result, []int{10, 11, 0, 1, 2}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func BenchmarkToSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		iter := Filter(Range(0, 10000, 1), func(int) bool { return true })
+		ToSlice(iter)
+	}
+}
+
+func BenchmarkToSliceCap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		iter := Filter(Range(0, 10000, 1), func(int) bool { return true })
+		ToSliceCap(iter, 10000)
+	}
+}
+
+func TestFromChannelContext(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		ch := make(chan int, 2)
+		ch <- 1
+		ch <- 2
+		close(ch)
+		result := ToSlice(FromChannelContext(context.Background(), ch))
+		if !reflect.DeepEqual(result, []int{1, 2}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("cancel pending", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		iter := FromChannelContext(ctx, make(chan int))
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		if val, ok := iter.Next(); ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("cancel buffered", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 10)
+		for i := 0; i < 10; i++ {
+			ch <- i
+		}
+		cancel()
+		iter := FromChannelContext(ctx, ch)
+		for i := 0; i < 10; i++ {
+			if val, ok := iter.Next(); ok {
+				t.Fatalf("Unexpected: %v", val)
+			}
+		}
+	})
+}
+
 func TestToChannel(t *testing.T) {
 	t.Run("cancel unconsumed", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -120,6 +294,39 @@ func TestToChannel(t *testing.T) {
 	})
 }
 
+func TestToChannelErr(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		out, errs := ToChannelErr(context.Background(), Range(0, 4, 1))
+		result := ToSlice(FromChannel(out))
+		if !reflect.DeepEqual(result, []int{0, 1, 2, 3}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if err, ok := <-errs; ok {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		iter := FromCSV(strings.NewReader("a,b\nc,d\ne\nf,g\n"))
+		out, errs := ToChannelErr[[]string](context.Background(), iter)
+		result := ToSlice(FromChannel(out))
+		if !reflect.DeepEqual(result, [][]string{{"a", "b"}, {"c", "d"}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if err := <-errs; !errors.Is(err, csv.ErrFieldCount) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("cancel unconsumed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		out, errs := ToChannelErr(ctx, Range(0, 4, 1))
+		<-out
+		cancel()
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
+
 func TestGo(t *testing.T) {
 	t.Run("items", func(t *testing.T) {
 		iter := FromSlice([]int{1, 2, 3, 4})
@@ -131,6 +338,37 @@ func TestGo(t *testing.T) {
 	})
 }
 
+func TestBuffer(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		iter := Buffer(context.Background(), Range(0, 10, 1), 4)
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, ToSlice(Range(0, 10, 1))) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("cancel unconsumed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		iter := Buffer(ctx, Repeat(1), 4)
+		iter.Next()
+		cancel()
+		// The source is infinite, so this only terminates if the goroutine stops.
+		Count(iter)
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	iter := WithContext(ctx, Repeat(1))
+	iter = Map(iter, func(i int) int {
+		cancel()
+		return i
+	})
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{1}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
 func TestFromMap(t *testing.T) {
 	m := map[string]int{
 		"x": 1,
@@ -150,6 +388,50 @@ func TestFromMap(t *testing.T) {
 	}
 }
 
+func TestFromMapSorted(t *testing.T) {
+	m := map[string]int{
+		"z": 3,
+		"x": 1,
+		"y": 2,
+	}
+	result := ToSlice(FromMapSorted(m))
+	expect := []MapEntry[string, int]{
+		{Key: "x", Val: 1},
+		{Key: "y", Val: 2},
+		{Key: "z", Val: 3},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestFromMapKeys(t *testing.T) {
+	m := map[string]int{
+		"x": 1,
+		"y": 2,
+		"z": 3,
+	}
+	result := ToSet(FromMapKeys(m))
+	expect := map[string]struct{}{"x": {}, "y": {}, "z": {}}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestFromMapValues(t *testing.T) {
+	m := map[string]int{
+		"x": 1,
+		"y": 2,
+		"z": 3,
+	}
+	result := ToSlice(FromMapValues(m))
+	sort.Ints(result)
+	expect := []int{1, 2, 3}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
 func TestToMap(t *testing.T) {
 	iter := FromSlice([]MapEntry[string, int]{
 		{Key: "x", Val: 1},
@@ -167,8 +449,76 @@ func TestToMap(t *testing.T) {
 	}
 }
 
+func TestToMapGroup(t *testing.T) {
+	iter := FromSlice([]MapEntry[string, int]{
+		{Key: "x", Val: 1},
+		{Key: "y", Val: 2},
+		{Key: "x", Val: 3},
+	})
+	result := ToMapGroup(iter)
+	expect := map[string][]int{
+		"x": {1, 3},
+		"y": {2},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	iter := FromSlice([]user{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 1, Name: "carol"},
+	})
+	result := Index(iter, func(u user) int { return u.ID })
+	expect := map[int]user{
+		1: {ID: 1, Name: "carol"},
+		2: {ID: 2, Name: "bob"},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestToSet(t *testing.T) {
+	iter := FromSlice([]string{"x", "y", "x", "z", "y"})
+	result := ToSet(iter)
+	expect := map[string]struct{}{"x": {}, "y": {}, "z": {}}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	iter := FromSlice([]Pair[int, string]{
+		{A: 1, B: "x"},
+		{A: 2, B: "y"},
+		{A: 3, B: "z"},
+	})
+	as, bs := Unzip(iter)
+	if !reflect.DeepEqual(as, []int{1, 2, 3}) {
+		t.Fatalf("Unexpected: %v", as)
+	}
+	if !reflect.DeepEqual(bs, []string{"x", "y", "z"}) {
+		t.Fatalf("Unexpected: %v", bs)
+	}
+}
+
 type byMapEntryKey[K constraints.Ordered, V any] []MapEntry[K, V]
 
 func (s byMapEntryKey[K, V]) Len() int           { return len(s) }
 func (s byMapEntryKey[K, V]) Less(i, j int) bool { return s[i].Key < s[j].Key }
 func (s byMapEntryKey[K, V]) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func BenchmarkOnce(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		iter := Once(i)
+		iter.Next()
+	}
+}
@@ -1,7 +1,15 @@
 package iterator
 
 import (
+	"container/list"
+	"math"
+	"math/bits"
+	"reflect"
+	"strings"
+	"sync"
+
 	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
 )
 
 // Map returns a new iterator which applies a function to all items from the input iterator which
@@ -31,6 +39,59 @@ func (iter *mapIterator[T, O]) Count() int {
 	return Count(iter.from)
 }
 
+// MapErr applies a function to all items from the specified iterator as Map does, but skips the
+// items for which the function returns an error. The errors are collected and can be retrieved with
+// the returned function, which is best called after the iterator has been consumed.
+func MapErr[T any, O any](from Iterator[T], mapFunc func(T) (O, error)) (Iterator[O], func() []error) {
+	errs := []error{}
+	iter := FilterMap(from, func(item T) (O, bool) {
+		mapped, err := mapFunc(item)
+		if err != nil {
+			errs = append(errs, err)
+			return mapped, false
+		}
+		return mapped, true
+	})
+	return iter, func() []error { return errs }
+}
+
+// MapIndexed is like Map, but also passes the zero-based index of each item to the mapping
+// function.
+func MapIndexed[T any, O any](from Iterator[T], mapFunc func(index int, item T) O) Iterator[O] {
+	return &mapIndexedIterator[T, O]{from: from, mapFunc: mapFunc}
+}
+
+type mapIndexedIterator[T any, O any] struct {
+	from    Iterator[T]
+	mapFunc func(int, T) O
+	index   int
+}
+
+func (iter *mapIndexedIterator[T, O]) Next() (O, bool) {
+	item, ok := iter.from.Next()
+	if !ok {
+		var zero O
+		return zero, false
+	}
+	mapped := iter.mapFunc(iter.index, item)
+	iter.index++
+	return mapped, true
+}
+
+func (iter *mapIndexedIterator[T, O]) Count() int {
+	count := Count(iter.from)
+	iter.index += count
+	return count
+}
+
+// WithIndex returns an iterator that pairs each item from the specified iterator with its
+// zero-based index.
+func WithIndex[T any](from Iterator[T]) Iterator[Pair[int, T]] {
+	return MapIndexed(from, func(index int, item T) Pair[int, T] {
+		return Pair[int, T]{A: index, B: item}
+	})
+}
+
 // FilterMap applies a function to all items from the specified iterator as Map does, but culls the
 // results which are accompanied by false.
 func FilterMap[T any, O any](from Iterator[T], mapFunc func(T) (O, bool)) Iterator[O] {
@@ -53,8 +114,51 @@ func (iter *filterMapIterator[T, O]) Next() (O, bool) {
 	return zero, false
 }
 
-// Flatten applies a function to all items of the specified iterator, returning an iterator for each
-// item. The resulting iterators are then concatenated into a single iterator.
+// MapWhile applies a function to all items from the specified iterator as Map does, but stops
+// entirely the first time the function returns false. The item for which false was returned is
+// consumed.
+func MapWhile[T any, O any](from Iterator[T], mapFunc func(T) (O, bool)) Iterator[O] {
+	return &mapWhileIterator[T, O]{from: from, mapFunc: mapFunc}
+}
+
+type mapWhileIterator[T any, O any] struct {
+	from    Iterator[T]
+	mapFunc func(T) (O, bool)
+}
+
+func (iter *mapWhileIterator[T, O]) Next() (O, bool) {
+	var zero O
+	if iter.from == nil {
+		return zero, false
+	}
+	item, ok := iter.from.Next()
+	if !ok {
+		return zero, false
+	}
+	mapped, ok := iter.mapFunc(item)
+	if !ok {
+		iter.from = nil
+		return zero, false
+	}
+	return mapped, true
+}
+
+// OfType returns an iterator over the items from the specified iterator of which the dynamic type
+// is T. Other items are skipped.
+func OfType[T any](from Iterator[any]) Iterator[T] {
+	return FilterMap(from, func(item any) (T, bool) {
+		typed, ok := item.(T)
+		return typed, ok
+	})
+}
+
+// Flatten concatenates the iterators returned by the specified iterator into a single iterator.
+//
+// To flatten items that are not iterators themselves, Map them to iterators first.
+//
+// The returned iterator implements Counter. Counting consumes the outer iterator and counts each of
+// the inner iterators, which does not pull any items if the inner iterators implement Counter
+// themselves.
 func Flatten[T any](from Iterator[Iterator[T]]) Iterator[T] {
 	return &flattenIterator[T]{from: from}
 }
@@ -93,6 +197,38 @@ func (iter *flattenIterator[T]) Count() int {
 	return count
 }
 
+// FlatMapSlice applies the map function to all items from the specified iterator and returns the
+// items of the resulting slices in order. Items for which the map function returns false or an
+// empty slice produce no output.
+//
+// This is equivalent to mapping to FromSlice and calling Flatten, but avoids allocating an
+// iterator for every item, which matters when many items map to no results.
+func FlatMapSlice[T any, O any](from Iterator[T], mapFunc func(T) ([]O, bool)) Iterator[O] {
+	return &flatMapSliceIterator[T, O]{from: from, mapFunc: mapFunc}
+}
+
+type flatMapSliceIterator[T any, O any] struct {
+	from    Iterator[T]
+	mapFunc func(T) ([]O, bool)
+	head    []O
+}
+
+func (iter *flatMapSliceIterator[T, O]) Next() (O, bool) {
+	for len(iter.head) == 0 {
+		item, ok := iter.from.Next()
+		if !ok {
+			var zero O
+			return zero, false
+		}
+		if mapped, ok := iter.mapFunc(item); ok {
+			iter.head = mapped
+		}
+	}
+	item := iter.head[0]
+	iter.head = iter.head[1:]
+	return item, true
+}
+
 // Filter returns a new iterator that returns only the items that pass the test of the specified
 // filter function.
 //
@@ -116,6 +252,43 @@ func (iter *filterIterator[T]) Next() (T, bool) {
 	return zero, false
 }
 
+// FilterIndexed is like Filter, but also passes the zero-based index of each item to the filter
+// function.
+func FilterIndexed[T any](from Iterator[T], filterFunc func(index int, item T) bool) Iterator[T] {
+	return &filterIndexedIterator[T]{from: from, filterFunc: filterFunc}
+}
+
+type filterIndexedIterator[T any] struct {
+	from       Iterator[T]
+	filterFunc func(int, T) bool
+	index      int
+}
+
+func (iter *filterIndexedIterator[T]) Next() (T, bool) {
+	for item, ok := iter.from.Next(); ok; item, ok = iter.from.Next() {
+		index := iter.index
+		iter.index++
+		if iter.filterFunc(index, item) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// TakeEvery returns an iterator that skips the first offset items and then returns every step-th
+// item, starting with the item at the offset.
+//
+// TakeEvery panics if step is not positive.
+func TakeEvery[T any](from Iterator[T], step, offset int) Iterator[T] {
+	if step <= 0 {
+		panic("TakeEvery: step must be positive")
+	}
+	return FilterIndexed(from, func(index int, _ T) bool {
+		return index >= offset && (index-offset)%step == 0
+	})
+}
+
 // Take limits the number of items returned by an iterator to the specified count.
 func Take[T any](from Iterator[T], num int) Iterator[T] {
 	return &takeIterator[T]{from: from, num: num}
@@ -144,8 +317,12 @@ func (iter *takeIterator[T]) Count() int {
 		count = counter.Count()
 	} else {
 		// If `Count(from)` is larger than num we are potentially doing more work than needed. So we
-		// have our own count loop with a limit on the loop condition that exits early.
-		for _, ok := iter.from.Next(); ok && count < iter.num; _, ok = iter.from.Next() {
+		// have our own count loop with a limit on the loop condition that exits early, without
+		// pulling more than num items from the source.
+		for count < iter.num {
+			if _, ok := iter.from.Next(); !ok {
+				break
+			}
 			count++
 		}
 	}
@@ -165,8 +342,49 @@ func Reduce[T any, O any](from Iterator[T], reduceFunc func(O, T) O, initial O)
 	return accum
 }
 
+// ReduceIndexed is like Reduce, but also passes the zero-based index of each item to the reduce
+// function.
+func ReduceIndexed[T any, O any](from Iterator[T], reduceFunc func(accum O, index int, item T) O, initial O) O {
+	accum := initial
+	index := 0
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		accum = reduceFunc(accum, index, item)
+		index++
+	}
+	return accum
+}
+
+// ReduceWhile is like Reduce, but stops consuming the iterator as soon as the reduce function
+// returns false. The accumulator returned along with false is the result.
+func ReduceWhile[T any, O any](from Iterator[T], reduceFunc func(O, T) (O, bool), initial O) O {
+	accum := initial
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		var cont bool
+		if accum, cont = reduceFunc(accum, item); !cont {
+			break
+		}
+	}
+	return accum
+}
+
+// FoldRight buffers all items from the iterator and folds them from the last item towards the
+// first. Unlike Reduce, this requires the whole iterator to be held in memory.
+func FoldRight[T any, O any](from Iterator[T], foldFunc func(T, O) O, initial O) O {
+	items := ToSlice(from)
+	accum := initial
+	for i := len(items) - 1; i >= 0; i-- {
+		accum = foldFunc(items[i], accum)
+	}
+	return accum
+}
+
 // Counter can optionally be implemented by iterators to provide a specialized implementation of
 // Count. Implementations must ensure that after Count was called, Next will return no more items.
+//
+// Sources of which the length is known, such as FromSlice and Range, implement Counter, as do
+// operations of which the number of items follows from their inputs, such as Map, Take and
+// Flatten. Operations of which the number of items depends on the values of the items, such as
+// Filter, FilterMap and MapWhile, do not implement Counter, as they have to inspect every item.
 type Counter[T any] interface {
 	Iterator[T]
 	Count() int
@@ -186,6 +404,75 @@ func Count[T any](from Iterator[T]) int {
 	return count
 }
 
+// CountFunc consumes the entire iterator and returns the number of items that pass the predicate.
+func CountFunc[T any](from Iterator[T], predicate func(T) bool) int {
+	count := 0
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if predicate(item) {
+			count++
+		}
+	}
+	return count
+}
+
+// Drain consumes the entire iterator and discards the items. It is useful for running an iterator
+// chain only for its side effects.
+func Drain[T any](from Iterator[T]) {
+	Count(from)
+}
+
+// CountBy consumes the entire iterator and counts the items grouped by the key returned by the
+// specified key function.
+func CountBy[T any, K comparable](from Iterator[T], keyFunc func(T) K) map[K]int {
+	counts := map[K]int{}
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		counts[keyFunc(item)]++
+	}
+	return counts
+}
+
+// CountDistinct consumes the entire iterator and returns the number of distinct items.
+func CountDistinct[T comparable](from Iterator[T]) int {
+	return len(ToSet(from))
+}
+
+// ApproxCountDistinct consumes the entire iterator and estimates the number of distinct items using
+// the HyperLogLog algorithm. It uses a fixed amount of memory (16KiB) regardless of the number of
+// items, at the cost of a standard error of about 0.8%.
+//
+// The hash function should distribute its output uniformly over all 64 bits, equal items must hash
+// to the same value.
+func ApproxCountDistinct[T any](from Iterator[T], hashFunc func(T) uint64) uint64 {
+	const precision = 14
+	const m = 1 << precision
+	var registers [m]uint8
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		hash := hashFunc(item)
+		index := hash >> (64 - precision)
+		// Set a guard bit so the rank is bounded if all remaining bits are zero.
+		rank := uint8(bits.LeadingZeros64(hash<<precision|1<<(precision-1))) + 1
+		if rank > registers[index] {
+			registers[index] = rank
+		}
+	}
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Small range correction using linear counting.
+		estimate = m * math.Log(float64(m)/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
 // Sum adds all the items from the iterator.
 func Sum[T Number](from Iterator[T]) T {
 	var zero T
@@ -194,6 +481,87 @@ func Sum[T Number](from Iterator[T]) T {
 	}, zero)
 }
 
+// SumKahan adds all the items from the iterator using compensated (Kahan) summation.
+//
+// Plain Sum accumulates rounding errors which become significant when adding many small values to
+// a large total. SumKahan keeps track of the lost low-order bits at the cost of a few extra
+// floating point operations per item.
+func SumKahan[T constraints.Float](from Iterator[T]) T {
+	var sum, compensation T
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		y := item - compensation
+		t := sum + y
+		compensation = (t - sum) - y
+		sum = t
+	}
+	return sum
+}
+
+// Average computes the arithmetic mean of all items from the iterator. False is returned if the
+// iterator is empty.
+//
+// The items are summed using SumKahan to limit the loss of precision on large streams.
+func Average[T Number](from Iterator[T]) (float64, bool) {
+	count := 0
+	floats := Map(from, func(item T) float64 {
+		count++
+		return float64(item)
+	})
+	sum := SumKahan(floats)
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// Variance computes the population variance of the items from the iterator in a single pass using
+// Welford's algorithm. False is returned if the iterator is empty.
+func Variance[T Number](from Iterator[T]) (float64, bool) {
+	count, m2 := welford(from)
+	if count == 0 {
+		return 0, false
+	}
+	return m2 / float64(count), true
+}
+
+// SampleVariance is like Variance, but computes the sample variance using Bessel's correction.
+// False is returned if the iterator has fewer than two items.
+func SampleVariance[T Number](from Iterator[T]) (float64, bool) {
+	count, m2 := welford(from)
+	if count < 2 {
+		return 0, false
+	}
+	return m2 / float64(count-1), true
+}
+
+// StdDev computes the population standard deviation of the items from the iterator. False is
+// returned if the iterator is empty.
+func StdDev[T Number](from Iterator[T]) (float64, bool) {
+	variance, ok := Variance(from)
+	return math.Sqrt(variance), ok
+}
+
+// SampleStdDev computes the sample standard deviation of the items from the iterator. False is
+// returned if the iterator has fewer than two items.
+func SampleStdDev[T Number](from Iterator[T]) (float64, bool) {
+	variance, ok := SampleVariance(from)
+	return math.Sqrt(variance), ok
+}
+
+// welford returns the number of items and the sum of squared differences from the mean.
+func welford[T Number](from Iterator[T]) (int, float64) {
+	count := 0
+	var mean, m2 float64
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		count++
+		x := float64(item)
+		delta := x - mean
+		mean += delta / float64(count)
+		m2 += delta * (x - mean)
+	}
+	return count, m2
+}
+
 func Min[T constraints.Ordered](from Iterator[T]) (T, bool) {
 	init, ok := from.Next()
 	if !ok {
@@ -224,17 +592,1032 @@ func Max[T constraints.Ordered](from Iterator[T]) (T, bool) {
 	return max, true
 }
 
+// MinIndex returns the smallest item from the iterator along with its zero-based index. The first
+// occurrence is returned if there are multiple smallest items. False is returned if the iterator is
+// empty.
+func MinIndex[T constraints.Ordered](from Iterator[T]) (index int, value T, ok bool) {
+	return extremeIndex(from, func(a, b T) bool { return a < b })
+}
+
+// MaxIndex returns the largest item from the iterator along with its zero-based index. The first
+// occurrence is returned if there are multiple largest items. False is returned if the iterator is
+// empty.
+func MaxIndex[T constraints.Ordered](from Iterator[T]) (index int, value T, ok bool) {
+	return extremeIndex(from, func(a, b T) bool { return a > b })
+}
+
+func extremeIndex[T constraints.Ordered](from Iterator[T], better func(a, b T) bool) (int, T, bool) {
+	value, ok := from.Next()
+	if !ok {
+		return 0, value, false
+	}
+	index := 0
+	i := 1
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if better(item, value) {
+			index, value = i, item
+		}
+		i++
+	}
+	return index, value, true
+}
+
+// AllMin returns all items that are equal to the smallest item from the iterator, in the order in
+// which they were returned. An empty slice is returned if the iterator is empty.
+func AllMin[T constraints.Ordered](from Iterator[T]) []T {
+	return allExtremes(from, func(a, b T) bool { return a < b })
+}
+
+// AllMax returns all items that are equal to the largest item from the iterator, in the order in
+// which they were returned. An empty slice is returned if the iterator is empty.
+func AllMax[T constraints.Ordered](from Iterator[T]) []T {
+	return allExtremes(from, func(a, b T) bool { return a > b })
+}
+
+func allExtremes[T constraints.Ordered](from Iterator[T], better func(a, b T) bool) []T {
+	extremes := []T{}
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if len(extremes) == 0 || better(item, extremes[0]) {
+			extremes = append(extremes[:0], item)
+		} else if item == extremes[0] {
+			extremes = append(extremes, item)
+		}
+	}
+	return extremes
+}
+
 // Join concatenates the strings from an iterator into a single string, with the items separated by
 // the specified separator string.
 func Join[T ~string](from Iterator[T], sep string) string {
-	following := false
-	accum := ""
+	return JoinFunc(from, sep, func(item T) string {
+		return string(item)
+	})
+}
+
+// FirstOr returns the first item from the iterator, or the fallback if the iterator is empty.
+func FirstOr[T any](from Iterator[T], fallback T) T {
+	if item, ok := from.Next(); ok {
+		return item
+	}
+	return fallback
+}
+
+// ElementAtOr returns the item at the zero-based index n, or the fallback if the iterator has too
+// few items. The items preceding the index are consumed.
+func ElementAtOr[T any](from Iterator[T], n int, fallback T) T {
+	if n < 0 {
+		return fallback
+	}
 	for item, ok := from.Next(); ok; item, ok = from.Next() {
-		if following {
-			accum += sep
+		if n == 0 {
+			return item
 		}
-		following = true
-		accum += string(item)
+		n--
 	}
-	return accum
+	return fallback
+}
+
+// DefaultIfEmpty returns an iterator that returns all items from the specified iterator, or only
+// the specified value if the iterator turns out to be empty.
+func DefaultIfEmpty[T any](from Iterator[T], value T) Iterator[T] {
+	return &defaultIfEmptyIterator[T]{from: from, value: value}
+}
+
+type defaultIfEmptyIterator[T any] struct {
+	from    Iterator[T]
+	value   T
+	started bool
+}
+
+func (iter *defaultIfEmptyIterator[T]) Next() (T, bool) {
+	if iter.started {
+		return iter.from.Next()
+	}
+	iter.started = true
+	if item, ok := iter.from.Next(); ok {
+		return item, true
+	}
+	iter.from = Empty[T]()
+	return iter.value, true
+}
+
+// Interleave returns an iterator that takes one item from each of the specified iterators in turn.
+// Iterators that are exhausted are skipped until all of them are.
+func Interleave[T any](iters ...Iterator[T]) Iterator[T] {
+	return &interleaveIterator[T]{iters: append([]Iterator[T]{}, iters...)}
+}
+
+type interleaveIterator[T any] struct {
+	iters []Iterator[T]
+	index int
+}
+
+func (iter *interleaveIterator[T]) Next() (T, bool) {
+	for len(iter.iters) > 0 {
+		if iter.index >= len(iter.iters) {
+			iter.index = 0
+		}
+		item, ok := iter.iters[iter.index].Next()
+		if ok {
+			iter.index++
+			return item, true
+		}
+		iter.iters = append(iter.iters[:iter.index], iter.iters[iter.index+1:]...)
+	}
+	var zero T
+	return zero, false
+}
+
+// Memoize returns a function that creates independent iterators over the items of the specified
+// iterator. Items are pulled from the source lazily and at most once, regardless of how many
+// iterators are created. The created iterators may be consumed from concurrent goroutines.
+//
+// All items that have been pulled from the source are retained for as long as the returned function
+// is reachable, so the memory usage is unbounded for infinite sources.
+func Memoize[T any](from Iterator[T]) func() Iterator[T] {
+	cache := &memoizeCache[T]{from: from}
+	return func() Iterator[T] {
+		return &memoizeIterator[T]{cache: cache}
+	}
+}
+
+type memoizeCache[T any] struct {
+	lock  sync.Mutex
+	from  Iterator[T]
+	items []T
+}
+
+func (cache *memoizeCache[T]) get(index int) (T, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	for cache.from != nil && len(cache.items) <= index {
+		item, ok := cache.from.Next()
+		if !ok {
+			cache.from = nil
+			break
+		}
+		cache.items = append(cache.items, item)
+	}
+	if index < len(cache.items) {
+		return cache.items[index], true
+	}
+	var zero T
+	return zero, false
+}
+
+type memoizeIterator[T any] struct {
+	cache *memoizeCache[T]
+	index int
+}
+
+func (iter *memoizeIterator[T]) Next() (T, bool) {
+	item, ok := iter.cache.get(iter.index)
+	if ok {
+		iter.index++
+	}
+	return item, ok
+}
+
+// Progress returns an iterator that returns all items from the specified iterator and calls fn with
+// the number of items returned so far each time another `every` items have passed through.
+//
+// Progress panics if every is not positive.
+func Progress[T any](from Iterator[T], every int, fn func(count int)) Iterator[T] {
+	if every <= 0 {
+		panic("Progress: every must be positive")
+	}
+	return &progressIterator[T]{from: from, every: every, fn: fn}
+}
+
+type progressIterator[T any] struct {
+	from  Iterator[T]
+	every int
+	fn    func(count int)
+	count int
+}
+
+func (iter *progressIterator[T]) Next() (T, bool) {
+	item, ok := iter.from.Next()
+	if ok {
+		iter.count++
+		if iter.count%iter.every == 0 {
+			iter.fn(iter.count)
+		}
+	}
+	return item, ok
+}
+
+// Span splits the specified iterator at the first item that does not pass the predicate. The
+// leading items that pass are returned as a slice, the returned iterator yields the remaining items
+// starting at the first item that did not pass.
+func Span[T any](from Iterator[T], predicate func(T) bool) ([]T, Iterator[T]) {
+	head := []T{}
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if !predicate(item) {
+			return head, Prepend(item, from)
+		}
+		head = append(head, item)
+	}
+	return head, Empty[T]()
+}
+
+// SplitAt splits the specified iterator after the first n items. The first n items are returned as
+// a slice, the returned iterator yields the remaining items. If the iterator has fewer than n items,
+// the slice holds all items and the returned iterator is empty.
+func SplitAt[T any](from Iterator[T], n int) ([]T, Iterator[T]) {
+	return ToSlice(Take(from, n)), from
+}
+
+// Differences returns an iterator over the differences between each consecutive pair of items from
+// the specified iterator. An iterator with fewer than two items results in an empty iterator.
+func Differences[T Number](from Iterator[T]) Iterator[T] {
+	return &differencesIterator[T]{from: from}
+}
+
+type differencesIterator[T Number] struct {
+	from     Iterator[T]
+	prev     T
+	havePrev bool
+}
+
+func (iter *differencesIterator[T]) Next() (T, bool) {
+	if !iter.havePrev {
+		prev, ok := iter.from.Next()
+		if !ok {
+			return prev, false
+		}
+		iter.prev, iter.havePrev = prev, true
+	}
+	item, ok := iter.from.Next()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	diff := item - iter.prev
+	iter.prev = item
+	return diff, true
+}
+
+func (iter *differencesIterator[T]) Count() int {
+	count := Count(iter.from)
+	if !iter.havePrev && count > 0 {
+		count--
+	}
+	iter.havePrev = true
+	return count
+}
+
+// MovingAverage returns an iterator over the averages of each sliding window of the specified size.
+// A running sum is maintained, so each step takes constant time regardless of the window size. An
+// iterator with fewer items than the window size results in an empty iterator.
+//
+// MovingAverage panics if window is not positive.
+func MovingAverage[T Number](from Iterator[T], window int) Iterator[float64] {
+	if window <= 0 {
+		panic("MovingAverage: window must be positive")
+	}
+	return &movingAverageIterator[T]{from: from, window: make([]T, 0, window)}
+}
+
+type movingAverageIterator[T Number] struct {
+	from   Iterator[T]
+	window []T
+	oldest int
+	sum    float64
+}
+
+func (iter *movingAverageIterator[T]) Next() (float64, bool) {
+	for len(iter.window) < cap(iter.window)-1 {
+		item, ok := iter.from.Next()
+		if !ok {
+			return 0, false
+		}
+		iter.window = append(iter.window, item)
+		iter.sum += float64(item)
+	}
+	item, ok := iter.from.Next()
+	if !ok {
+		return 0, false
+	}
+	if len(iter.window) < cap(iter.window) {
+		iter.window = append(iter.window, item)
+	} else {
+		iter.sum -= float64(iter.window[iter.oldest])
+		iter.window[iter.oldest] = item
+		iter.oldest = (iter.oldest + 1) % len(iter.window)
+	}
+	iter.sum += float64(item)
+	return iter.sum / float64(len(iter.window)), true
+}
+
+// Histogram counts the items from the iterator into the specified number of equal-width buckets
+// spanning min to max. Every bucket includes its lower edge, the last bucket also includes max.
+// Items that fall outside of the range and NaN items are ignored.
+//
+// Histogram panics if max is not greater than min or if buckets is not positive.
+func Histogram[T Number](from Iterator[T], min, max T, buckets int) []int {
+	if !(max > min) { // Also catches NaN.
+		panic("Histogram: max must be greater than min")
+	} else if buckets <= 0 {
+		panic("Histogram: buckets must be positive")
+	}
+	counts := make([]int, buckets)
+	// Convert before subtracting, the difference may not fit in T.
+	width := (float64(max) - float64(min)) / float64(buckets)
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if item != item || item < min || item > max {
+			continue // NaN or out of range.
+		}
+		index := int((float64(item) - float64(min)) / width)
+		if index < 0 {
+			index = 0
+		} else if index >= buckets {
+			index = buckets - 1
+		}
+		counts[index]++
+	}
+	return counts
+}
+
+// Median buffers and sorts all items from the iterator and returns the middle item. For an even
+// number of items, the lower of the two middle items is returned. False is returned if the iterator
+// is empty.
+func Median[T constraints.Ordered](from Iterator[T]) (T, bool) {
+	items := ToSlice(from)
+	if len(items) == 0 {
+		var zero T
+		return zero, false
+	}
+	slices.Sort(items)
+	return items[(len(items)-1)/2], true
+}
+
+// MedianFloat is like Median, but returns the average of the two middle items for an even number of
+// items.
+func MedianFloat[T Number](from Iterator[T]) (float64, bool) {
+	items := ToSlice(from)
+	if len(items) == 0 {
+		return 0, false
+	}
+	slices.Sort(items)
+	mid := len(items) / 2
+	if len(items)%2 == 0 {
+		return (float64(items[mid-1]) + float64(items[mid])) / 2, true
+	}
+	return float64(items[mid]), true
+}
+
+// Percentile buffers and sorts all items from the iterator and returns the item at the p-th
+// percentile using the nearest-rank method. False is returned if the iterator is empty.
+//
+// Percentile panics if p is not within 0 to 100.
+func Percentile[T constraints.Ordered](from Iterator[T], p float64) (T, bool) {
+	if !(p >= 0 && p <= 100) { // Also catches NaN.
+		panic("Percentile: p must be within 0 and 100")
+	}
+	items := ToSlice(from)
+	if len(items) == 0 {
+		var zero T
+		return zero, false
+	}
+	slices.Sort(items)
+	rank := int(math.Ceil(p / 100 * float64(len(items))))
+	if rank < 1 {
+		rank = 1
+	}
+	return items[rank-1], true
+}
+
+// Chunk returns an iterator that groups consecutive items into slices of the specified size. The
+// last chunk may hold fewer items. Each chunk is a newly allocated slice.
+//
+// Chunk panics if size is not positive.
+func Chunk[T any](from Iterator[T], size int) Iterator[[]T] {
+	if size <= 0 {
+		panic("Chunk: size must be positive")
+	}
+	return &chunkIterator[T]{from: from, size: size}
+}
+
+// ChunkReuse is like Chunk, but reuses the same backing array for every chunk to avoid an
+// allocation per chunk.
+//
+// The returned slice is only valid until the next call to Next, which overwrites its contents. A
+// chunk must therefore be fully processed or copied before advancing the iterator. Collecting the
+// chunks with e.g. ToSlice yields slices that all alias the same memory.
+//
+// ChunkReuse panics if size is not positive.
+func ChunkReuse[T any](from Iterator[T], size int) Iterator[[]T] {
+	if size <= 0 {
+		panic("ChunkReuse: size must be positive")
+	}
+	return &chunkIterator[T]{from: from, size: size, reuse: true}
+}
+
+type chunkIterator[T any] struct {
+	from  Iterator[T]
+	size  int
+	reuse bool
+	buf   []T
+}
+
+func (iter *chunkIterator[T]) Next() ([]T, bool) {
+	item, ok := iter.from.Next()
+	if !ok {
+		return nil, false
+	}
+	var chunk []T
+	if iter.reuse {
+		if iter.buf == nil {
+			iter.buf = make([]T, 0, iter.size)
+		}
+		chunk = iter.buf[:0]
+	} else {
+		chunk = make([]T, 0, iter.size)
+	}
+	chunk = append(chunk, item)
+	for len(chunk) < iter.size {
+		item, ok := iter.from.Next()
+		if !ok {
+			break
+		}
+		chunk = append(chunk, item)
+	}
+	return chunk, true
+}
+
+// ChunkReduce returns an iterator that reduces each consecutive chunk of the specified size to a
+// single value. Each chunk starts with a fresh accumulator from the initial function. The last
+// chunk may hold fewer items.
+//
+// ChunkReduce panics if size is not positive.
+func ChunkReduce[T any, O any](from Iterator[T], size int, reduceFunc func(O, T) O, initial func() O) Iterator[O] {
+	if size <= 0 {
+		panic("ChunkReduce: size must be positive")
+	}
+	return &chunkReduceIterator[T, O]{from: from, size: size, reduceFunc: reduceFunc, initial: initial}
+}
+
+type chunkReduceIterator[T any, O any] struct {
+	from       Iterator[T]
+	size       int
+	reduceFunc func(O, T) O
+	initial    func() O
+}
+
+func (iter *chunkReduceIterator[T, O]) Next() (O, bool) {
+	item, ok := iter.from.Next()
+	if !ok {
+		var zero O
+		return zero, false
+	}
+	accum := iter.reduceFunc(iter.initial(), item)
+	return Reduce(Take(iter.from, iter.size-1), iter.reduceFunc, accum), true
+}
+
+// GroupConsecutive returns an iterator that groups adjacent items for which the key function
+// returns the same key. A new group is started each time the key changes, so a key may occur in
+// multiple groups if the items are not sorted by it.
+func GroupConsecutive[T any, K comparable](from Iterator[T], keyFunc func(T) K) Iterator[MapEntry[K, []T]] {
+	return &groupConsecutiveIterator[T, K]{from: from, keyFunc: keyFunc}
+}
+
+type groupConsecutiveIterator[T any, K comparable] struct {
+	from        Iterator[T]
+	keyFunc     func(T) K
+	pending     T
+	havePending bool
+}
+
+func (iter *groupConsecutiveIterator[T, K]) Next() (MapEntry[K, []T], bool) {
+	if !iter.havePending {
+		item, ok := iter.from.Next()
+		if !ok {
+			return MapEntry[K, []T]{}, false
+		}
+		iter.pending = item
+	}
+	group := MapEntry[K, []T]{Key: iter.keyFunc(iter.pending), Val: []T{iter.pending}}
+	iter.havePending = false
+	for item, ok := iter.from.Next(); ok; item, ok = iter.from.Next() {
+		if iter.keyFunc(item) != group.Key {
+			iter.pending, iter.havePending = item, true
+			break
+		}
+		group.Val = append(group.Val, item)
+	}
+	return group, true
+}
+
+// RunLength returns an iterator that collapses runs of equal adjacent items into a single entry
+// holding the item and the length of the run.
+func RunLength[T comparable](from Iterator[T]) Iterator[RunLengthEntry[T]] {
+	return &runLengthIterator[T]{from: from}
+}
+
+// RunLengthEntry is a run of Count equal items as returned by RunLength.
+type RunLengthEntry[T comparable] struct {
+	Value T
+	Count int
+}
+
+type runLengthIterator[T comparable] struct {
+	from        Iterator[T]
+	pending     T
+	havePending bool
+}
+
+func (iter *runLengthIterator[T]) Next() (RunLengthEntry[T], bool) {
+	if !iter.havePending {
+		item, ok := iter.from.Next()
+		if !ok {
+			return RunLengthEntry[T]{}, false
+		}
+		iter.pending = item
+	}
+	run := RunLengthEntry[T]{Value: iter.pending, Count: 1}
+	iter.havePending = false
+	for item, ok := iter.from.Next(); ok; item, ok = iter.from.Next() {
+		if item != run.Value {
+			iter.pending, iter.havePending = item, true
+			break
+		}
+		run.Count++
+	}
+	return run, true
+}
+
+// ZipWith returns an iterator that combines the corresponding items of a and b using the specified
+// function. The iterator stops as soon as either of the inputs is exhausted.
+func ZipWith[A any, B any, O any](a Iterator[A], b Iterator[B], combine func(A, B) O) Iterator[O] {
+	return &zipWithIterator[A, B, O]{a: a, b: b, combine: combine}
+}
+
+type zipWithIterator[A any, B any, O any] struct {
+	a       Iterator[A]
+	b       Iterator[B]
+	combine func(A, B) O
+}
+
+func (iter *zipWithIterator[A, B, O]) Next() (O, bool) {
+	itemA, ok := iter.a.Next()
+	if !ok {
+		var zero O
+		return zero, false
+	}
+	itemB, ok := iter.b.Next()
+	if !ok {
+		var zero O
+		return zero, false
+	}
+	return iter.combine(itemA, itemB), true
+}
+
+func (iter *zipWithIterator[A, B, O]) Count() int {
+	// Either input may be infinite, even if it implements Counter, so both are advanced in
+	// lockstep to stop as soon as the shorter one runs out.
+	count := 0
+	for {
+		if _, ok := iter.a.Next(); !ok {
+			return count
+		}
+		if _, ok := iter.b.Next(); !ok {
+			return count
+		}
+		count++
+	}
+}
+
+// ZipLongest returns an iterator over the pairs of corresponding items of a and b. The iterator
+// continues until both inputs are exhausted, using the fill value in place of the items of the
+// input that ran out first.
+func ZipLongest[A any, B any](a Iterator[A], b Iterator[B], fillA A, fillB B) Iterator[Pair[A, B]] {
+	return &zipLongestIterator[A, B]{a: a, b: b, fillA: fillA, fillB: fillB}
+}
+
+type zipLongestIterator[A any, B any] struct {
+	a     Iterator[A]
+	b     Iterator[B]
+	fillA A
+	fillB B
+}
+
+func (iter *zipLongestIterator[A, B]) Next() (Pair[A, B], bool) {
+	itemA, okA := iter.a.Next()
+	itemB, okB := iter.b.Next()
+	if !okA && !okB {
+		return Pair[A, B]{}, false
+	}
+	if !okA {
+		itemA = iter.fillA
+	}
+	if !okB {
+		itemB = iter.fillB
+	}
+	return Pair[A, B]{A: itemA, B: itemB}, true
+}
+
+func (iter *zipLongestIterator[A, B]) Count() int {
+	countA, countB := Count(iter.a), Count(iter.b)
+	if countA > countB {
+		return countA
+	}
+	return countB
+}
+
+// Position returns the zero-based index of the first item that passes the predicate. False is
+// returned if no item passes. The iterator is consumed up to and including the matching item.
+func Position[T any](from Iterator[T], predicate func(T) bool) (int, bool) {
+	index := 0
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if predicate(item) {
+			return index, true
+		}
+		index++
+	}
+	return 0, false
+}
+
+// Fuse returns an iterator that keeps returning false once the specified iterator has returned
+// false for the first time. This enforces the contract of Iterator for sources that may not adhere
+// to it.
+func Fuse[T any](from Iterator[T]) Iterator[T] {
+	return &fuseIterator[T]{from: from}
+}
+
+type fuseIterator[T any] struct {
+	from Iterator[T]
+}
+
+func (iter *fuseIterator[T]) Next() (T, bool) {
+	if iter.from == nil {
+		var zero T
+		return zero, false
+	}
+	item, ok := iter.from.Next()
+	if !ok {
+		iter.from = nil
+	}
+	return item, ok
+}
+
+func (iter *fuseIterator[T]) Count() int {
+	if iter.from == nil {
+		return 0
+	}
+	count := Count(iter.from)
+	iter.from = nil
+	return count
+}
+
+// JoinFunc formats the items from an iterator using the specified function and concatenates the
+// results into a single string, with the items separated by the specified separator string.
+func JoinFunc[T any](from Iterator[T], sep string, format func(T) string) string {
+	var builder strings.Builder
+	joinTo(&builder, from, sep, format)
+	return builder.String()
+}
+
+// JoinWrap is like Join, but surrounds the result with the specified prefix and suffix. An empty
+// iterator results in just the prefix and suffix.
+func JoinWrap[T ~string](from Iterator[T], prefix, sep, suffix string) string {
+	var builder strings.Builder
+	builder.WriteString(prefix)
+	joinTo(&builder, from, sep, func(item T) string {
+		return string(item)
+	})
+	builder.WriteString(suffix)
+	return builder.String()
+}
+
+func joinTo[T any](builder *strings.Builder, from Iterator[T], sep string, format func(T) string) {
+	following := false
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if following {
+			builder.WriteString(sep)
+		}
+		following = true
+		builder.WriteString(format(item))
+	}
+}
+
+// AggregateWindow returns an iterator over the results of applying the aggregation function to
+// each sliding window of the specified size. Each window is passed as a new slice, which the
+// aggregation function may retain. An iterator with fewer items than the window size results in an
+// empty iterator.
+//
+// AggregateWindow panics if size is not positive.
+func AggregateWindow[T any, O any](from Iterator[T], size int, agg func([]T) O) Iterator[O] {
+	if size <= 0 {
+		panic("AggregateWindow: size must be positive")
+	}
+	return &aggregateWindowIterator[T, O]{from: from, size: size, agg: agg}
+}
+
+type aggregateWindowIterator[T any, O any] struct {
+	from   Iterator[T]
+	size   int
+	agg    func([]T) O
+	window []T
+}
+
+func (iter *aggregateWindowIterator[T, O]) Next() (O, bool) {
+	for len(iter.window) < iter.size {
+		item, ok := iter.from.Next()
+		if !ok {
+			var zero O
+			return zero, false
+		}
+		iter.window = append(iter.window, item)
+	}
+	window := append([]T{}, iter.window...)
+	iter.window = iter.window[1:]
+	return iter.agg(window), true
+}
+
+func (iter *aggregateWindowIterator[T, O]) Count() int {
+	count := len(iter.window) + Count(iter.from) - iter.size + 1
+	iter.window = nil
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+// Prepend returns an iterator that returns the specified item followed by all items from the
+// specified iterator.
+func Prepend[T any](item T, from Iterator[T]) Iterator[T] {
+	return Flatten(FromSlice([]Iterator[T]{Once(item), from}))
+}
+
+// Append returns an iterator that returns all items from the specified iterator followed by the
+// specified item.
+func Append[T any](from Iterator[T], item T) Iterator[T] {
+	return Flatten(FromSlice([]Iterator[T]{from, Once(item)}))
+}
+
+// TeeN returns n independent iterators that each return all items from the specified iterator.
+//
+// Items are buffered until all of the returned iterators have consumed them, so the memory usage
+// is bounded by the distance between the fastest and the slowest consumer. The returned iterators
+// may be consumed from concurrent goroutines.
+//
+// TeeN panics if n is not positive.
+func TeeN[T any](from Iterator[T], n int) []Iterator[T] {
+	if n <= 0 {
+		panic("TeeN: n must be positive")
+	}
+	buffer := &teeBuffer[T]{from: from, positions: make([]int, n)}
+	iters := make([]Iterator[T], n)
+	for i := range iters {
+		iters[i] = &teeIterator[T]{buffer: buffer, branch: i}
+	}
+	return iters
+}
+
+type teeBuffer[T any] struct {
+	lock      sync.Mutex
+	from      Iterator[T]
+	items     []T
+	offset    int // The position of items[0] in the source.
+	positions []int
+}
+
+func (buffer *teeBuffer[T]) next(branch int) (T, bool) {
+	buffer.lock.Lock()
+	defer buffer.lock.Unlock()
+
+	pos := buffer.positions[branch]
+	if pos-buffer.offset >= len(buffer.items) {
+		if buffer.from == nil {
+			var zero T
+			return zero, false
+		}
+		item, ok := buffer.from.Next()
+		if !ok {
+			buffer.from = nil
+			return item, false
+		}
+		buffer.items = append(buffer.items, item)
+	}
+	item := buffer.items[pos-buffer.offset]
+	buffer.positions[branch]++
+
+	// Release the items that all branches have consumed.
+	slowest := buffer.positions[0]
+	for _, p := range buffer.positions[1:] {
+		if p < slowest {
+			slowest = p
+		}
+	}
+	if slowest > buffer.offset {
+		var zero T
+		for i := 0; i < slowest-buffer.offset; i++ {
+			buffer.items[i] = zero
+		}
+		buffer.items = buffer.items[slowest-buffer.offset:]
+		buffer.offset = slowest
+	}
+	return item, true
+}
+
+type teeIterator[T any] struct {
+	buffer *teeBuffer[T]
+	branch int
+}
+
+func (iter *teeIterator[T]) Next() (T, bool) {
+	return iter.buffer.next(iter.branch)
+}
+
+// PeekIterator is an iterator that allows inspecting the next item without consuming it.
+type PeekIterator[T any] interface {
+	Iterator[T]
+	// Peek returns the item that the next call to Next will return, without consuming it.
+	Peek() (T, bool)
+}
+
+// Peekable wraps the specified iterator into a PeekIterator.
+func Peekable[T any](from Iterator[T]) PeekIterator[T] {
+	if peek, ok := from.(PeekIterator[T]); ok {
+		return peek
+	}
+	return &peekIterator[T]{from: from}
+}
+
+type peekIterator[T any] struct {
+	from       Iterator[T]
+	peeked     T
+	peekedOK   bool
+	havePeeked bool
+}
+
+func (iter *peekIterator[T]) Peek() (T, bool) {
+	if !iter.havePeeked {
+		iter.peeked, iter.peekedOK = iter.from.Next()
+		iter.havePeeked = true
+	}
+	return iter.peeked, iter.peekedOK
+}
+
+func (iter *peekIterator[T]) Next() (T, bool) {
+	if iter.havePeeked {
+		var zero T
+		item, ok := iter.peeked, iter.peekedOK
+		iter.peeked, iter.havePeeked = zero, false
+		return item, ok
+	}
+	return iter.from.Next()
+}
+
+func (iter *peekIterator[T]) Count() int {
+	count := 0
+	if iter.havePeeked && iter.peekedOK {
+		count++
+	}
+	var zero T
+	iter.peeked, iter.havePeeked = zero, false
+	return count + Count(iter.from)
+}
+
+// ConsumeWhile consumes items from the specified iterator for as long as they pass the predicate
+// and returns them. The first item that does not pass is not consumed and remains available
+// through Peek and Next.
+func ConsumeWhile[T any](p PeekIterator[T], predicate func(T) bool) []T {
+	items := []T{}
+	for item, ok := p.Peek(); ok && predicate(item); item, ok = p.Peek() {
+		p.Next()
+		items = append(items, item)
+	}
+	return items
+}
+
+// ChunkByWeight returns an iterator that groups consecutive items into slices of which the total
+// weight does not exceed maxWeight. A chunk is returned as soon as the next item would not fit. An
+// item that exceeds maxWeight on its own is returned as a chunk of one item.
+func ChunkByWeight[T any](from Iterator[T], maxWeight int, weightFunc func(T) int) Iterator[[]T] {
+	return &chunkByWeightIterator[T]{from: from, maxWeight: maxWeight, weightFunc: weightFunc}
+}
+
+type chunkByWeightIterator[T any] struct {
+	from        Iterator[T]
+	maxWeight   int
+	weightFunc  func(T) int
+	pending     T
+	havePending bool
+}
+
+func (iter *chunkByWeightIterator[T]) Next() ([]T, bool) {
+	if !iter.havePending {
+		item, ok := iter.from.Next()
+		if !ok {
+			return nil, false
+		}
+		iter.pending = item
+	}
+	chunk := []T{iter.pending}
+	weight := iter.weightFunc(iter.pending)
+	iter.havePending = false
+	for item, ok := iter.from.Next(); ok; item, ok = iter.from.Next() {
+		w := iter.weightFunc(item)
+		if weight+w > iter.maxWeight {
+			iter.pending, iter.havePending = item, true
+			break
+		}
+		chunk = append(chunk, item)
+		weight += w
+	}
+	return chunk, true
+}
+
+// DistinctBounded returns an iterator that skips items that are equal to one of the maxSeen most
+// recently seen distinct items. Seeing an item again marks it as recent.
+//
+// Because the least recently seen items are forgotten to bound the memory usage, duplicates that
+// are further apart than maxSeen distinct items are not detected and will be returned again.
+//
+// DistinctBounded panics if maxSeen is not positive.
+func DistinctBounded[T comparable](from Iterator[T], maxSeen int) Iterator[T] {
+	if maxSeen <= 0 {
+		panic("DistinctBounded: maxSeen must be positive")
+	}
+	return &distinctBoundedIterator[T]{
+		from:    from,
+		maxSeen: maxSeen,
+		recent:  list.New(),
+		seen:    map[T]*list.Element{},
+	}
+}
+
+type distinctBoundedIterator[T comparable] struct {
+	from    Iterator[T]
+	maxSeen int
+	recent  *list.List // The most recently seen item is at the front.
+	seen    map[T]*list.Element
+}
+
+func (iter *distinctBoundedIterator[T]) Next() (T, bool) {
+	for item, ok := iter.from.Next(); ok; item, ok = iter.from.Next() {
+		if elem, ok := iter.seen[item]; ok {
+			iter.recent.MoveToFront(elem)
+			continue
+		}
+		iter.seen[item] = iter.recent.PushFront(item)
+		if iter.recent.Len() > iter.maxSeen {
+			oldest := iter.recent.Remove(iter.recent.Back()).(T)
+			delete(iter.seen, oldest)
+		}
+		return item, true
+	}
+	var zero T
+	return zero, false
+}
+
+// FlattenDeep returns an iterator that recursively flattens slices up to the specified depth, so
+// that a depth of 1 flattens only the slices returned by the specified iterator. Items that are
+// not expanded are returned if they are of type T and are skipped otherwise.
+func FlattenDeep[T any](from Iterator[any], depth int) Iterator[T] {
+	return &flattenDeepIterator[T]{stack: []Iterator[any]{from}, depth: depth}
+}
+
+type flattenDeepIterator[T any] struct {
+	stack []Iterator[any]
+	depth int
+}
+
+func (iter *flattenDeepIterator[T]) Next() (T, bool) {
+	for len(iter.stack) > 0 {
+		level := len(iter.stack) - 1
+		item, ok := iter.stack[level].Next()
+		if !ok {
+			iter.stack = iter.stack[:level]
+			continue
+		}
+		if v := reflect.ValueOf(item); level < iter.depth && v.Kind() == reflect.Slice {
+			iter.stack = append(iter.stack, Map(Range(0, v.Len(), 1), func(i int) any {
+				return v.Index(i).Interface()
+			}))
+			continue
+		}
+		if leaf, ok := item.(T); ok {
+			return leaf, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Pipe applies the specified operations to the iterator in order. This allows writing chains of
+// operations of the same type from left to right rather than nesting the calls.
+func Pipe[T any](from Iterator[T], ops ...func(Iterator[T]) Iterator[T]) Iterator[T] {
+	for _, op := range ops {
+		from = op(from)
+	}
+	return from
+}
+
+// Then applies the specified operation to the iterator. Unlike Pipe, the operation may change the
+// type of the items, so calls can be chained from left to right.
+func Then[T any, O any](from Iterator[T], op func(Iterator[T]) Iterator[O]) Iterator[O] {
+	return op(from)
 }
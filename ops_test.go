@@ -3,7 +3,9 @@ package iterator
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -19,6 +21,40 @@ func TestMap(t *testing.T) {
 	testCounterImplementation(t, countIter, 3)
 }
 
+func TestMapErr(t *testing.T) {
+	iter, errs := MapErr(FromSlice([]string{"1", "x", "3", "y", "5"}), strconv.Atoi)
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{1, 3, 5}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if len(errs()) != 2 {
+		t.Fatalf("Unexpected errors: %v", errs())
+	}
+}
+
+func TestMapIndexed(t *testing.T) {
+	iter := MapIndexed(FromSlice([]string{"foo", "bar", "baz"}), func(i int, s string) string {
+		return fmt.Sprintf("%d: %s", i+1, s)
+	})
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []string{"1: foo", "2: bar", "3: baz"}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	countIter := MapIndexed(Range(0, 3, 1), func(i, item int) int { return i })
+	testCounterImplementation(t, countIter, 3)
+}
+
+func TestWithIndex(t *testing.T) {
+	result := ToSlice(WithIndex(FromSlice([]string{"x", "y", "z"})))
+	expect := []Pair[int, string]{{A: 0, B: "x"}, {A: 1, B: "y"}, {A: 2, B: "z"}}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, WithIndex(Range(0, 3, 1)), 3)
+}
+
 func TestFilterMap(t *testing.T) {
 	iter := FromSlice([]int{1, 2, 3, 4})
 	iter = FilterMap(iter, func(i int) (int, bool) {
@@ -31,6 +67,29 @@ func TestFilterMap(t *testing.T) {
 	}
 }
 
+func TestMapWhile(t *testing.T) {
+	source := FromSlice([]string{"1", "2", "x", "4"})
+	iter := MapWhile(source, func(s string) (int, bool) {
+		i, err := strconv.Atoi(s)
+		return i, err == nil
+	})
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{1, 2}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if rest := ToSlice(source); !reflect.DeepEqual(rest, []string{"4"}) {
+		t.Fatalf("Unexpected remainder: %v", rest)
+	}
+}
+
+func TestOfType(t *testing.T) {
+	iter := FromSlice([]any{1, "foo", 2, "bar", 3.0, 4})
+	result := ToSlice(OfType[int](iter))
+	if !reflect.DeepEqual(result, []int{1, 2, 4}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
 func TestFlatten(t *testing.T) {
 	iter0 := FromSlice([][]int{{0, 1, 2}, {10, 11, 12}})
 	iter1 := Map(iter0, FromSlice[int])
@@ -40,12 +99,29 @@ func TestFlatten(t *testing.T) {
 		t.Fatalf("Unexpected: %v", result)
 	}
 
+	iter3 := Flatten(FromSlice([]Iterator[int]{
+		FromSlice([]int{1, 2}),
+		Empty[int](),
+		FromSlice([]int{3}),
+	}))
+	if result := ToSlice(iter3); !reflect.DeepEqual(result, []int{1, 2, 3}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if result := ToSlice(Flatten(Empty[Iterator[int]]())); !reflect.DeepEqual(result, []int{}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
 	countIter0 := Flatten(Map(FromSlice([][]int{{0, 1, 2}, {100}, {10, 11}}), FromSlice[int]))
 	testCounterImplementation(t, countIter0, 6)
 
 	countIter1 := Flatten(Map(FromSlice([][]int{{0, 1, 2}, {100}, {10, 11}}), FromSlice[int]))
 	countIter1.Next() // Test whether the partially consumed iterator is included.
 	testCounterImplementation(t, countIter1, 5)
+
+	countIter2 := Flatten(Map(Ints(4), func(i int) Iterator[int] {
+		return Flatten(Map(Ints(i), Ints))
+	}))
+	testCounterImplementation(t, countIter2, 4)
 }
 
 func TestFilter(t *testing.T) {
@@ -57,13 +133,102 @@ func TestFilter(t *testing.T) {
 	}
 }
 
+func TestFilterIndexed(t *testing.T) {
+	isPrime := func(n int) bool {
+		if n < 2 {
+			return false
+		}
+		for d := 2; d*d <= n; d++ {
+			if n%d == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	iter := FilterIndexed(Range(100, 115, 1), func(i, _ int) bool { return isPrime(i) })
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{102, 103, 105, 107, 111, 113}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestTakeEvery(t *testing.T) {
+	result := ToSlice(TakeEvery(Range(0, 10, 1), 2, 1))
+	if !reflect.DeepEqual(result, []int{1, 3, 5, 7, 9}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	result = ToSlice(TakeEvery(Range(0, 10, 1), 3, 0))
+	if !reflect.DeepEqual(result, []int{0, 3, 6, 9}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	t.Run("panic on zero step", func(t *testing.T) {
+		var err interface{}
+		func() {
+			defer func() { err = recover() }()
+			TakeEvery(Range(0, 10, 1), 0, 0)
+		}()
+		if err == nil {
+			t.Fatalf("Expected panic")
+		}
+	})
+}
+
 func TestTake(t *testing.T) {
 	testCounterImplementation(t, Take(Repeat[int](1337), 10), 10)
 	testCounterImplementation(t, Take(Range[int](0, 20, 1), 10), 10)
+	testCounterImplementation(t, Take(Range[int](0, 5, 1), 10), 5)
+
+	t.Run("count does not overconsume", func(t *testing.T) {
+		ch := make(chan int, 4)
+		ch <- 1
+		ch <- 2
+		ch <- 3
+		ch <- 4
+		close(ch)
+		if count := Count(Take(FromChannel(ch), 2)); count != 2 {
+			t.Fatalf("Unexpected count: %v", count)
+		}
+		if len(ch) != 2 {
+			t.Fatalf("Unexpected remaining items: %v", len(ch))
+		}
+	})
 }
 
 // TestReduce is covered by other the other tests of the functions that use it.
 
+func TestReduceIndexed(t *testing.T) {
+	result := ReduceIndexed(FromSlice([]int{5, 6, 7}), func(accum, index, item int) int {
+		return accum + index*item
+	}, 0)
+	if result != 20 {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestReduceWhile(t *testing.T) {
+	result := ReduceWhile(Range(1, math.MaxInt, 1), func(accum, item int) (int, bool) {
+		accum += item
+		return accum, accum <= 10
+	}, 0)
+	if result != 15 {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestFoldRight(t *testing.T) {
+	type cons struct {
+		head int
+		tail *cons
+	}
+	result := FoldRight(FromSlice([]int{1, 2, 3}), func(item int, accum *cons) *cons {
+		return &cons{head: item, tail: accum}
+	}, nil)
+	expect := &cons{1, &cons{2, &cons{3, nil}}}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
 func TestCount(t *testing.T) {
 	iter := FromSlice([]int{0, 0, 0})
 	iter = Go(context.Background(), iter) // Ensure Counter is not implemented.
@@ -73,6 +238,84 @@ func TestCount(t *testing.T) {
 	}
 }
 
+func TestCountFunc(t *testing.T) {
+	result := CountFunc(Range(1, 7, 1), func(i int) bool { return i%2 == 0 })
+	if result != 3 {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	t.Run("counter", func(t *testing.T) {
+		iter := FromSlice([]int{1, 2, 3})
+		Drain(iter)
+		if val, ok := iter.Next(); ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("side effects", func(t *testing.T) {
+		ch := make(chan int, 3)
+		ch <- 1
+		ch <- 2
+		ch <- 3
+		close(ch)
+		Drain(FromChannel(ch))
+		if len(ch) != 0 {
+			t.Fatalf("Unexpected remaining items: %v", len(ch))
+		}
+	})
+}
+
+func TestCountBy(t *testing.T) {
+	iter := FromSlice([]string{"apple", "avocado", "banana", "cherry", "cranberry", "citrus"})
+	result := CountBy(iter, func(s string) byte { return s[0] })
+	expect := map[byte]int{'a': 2, 'b': 1, 'c': 3}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestCountDistinct(t *testing.T) {
+	t.Run("repeats", func(t *testing.T) {
+		result := CountDistinct(FromSlice([]int{1, 2, 1, 3, 2, 1}))
+		if result != 3 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		result := CountDistinct(Empty[int]())
+		if result != 0 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestApproxCountDistinct(t *testing.T) {
+	// splitmix64 finalizer, to spread the bits of consecutive integers.
+	hash := func(i int) uint64 {
+		x := uint64(i) + 0x9e3779b97f4a7c15
+		x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+		x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+		return x ^ (x >> 31)
+	}
+	for _, n := range []int{100, 10000, 200000} {
+		t.Run(fmt.Sprintf("distinct %d", n), func(t *testing.T) {
+			// Each value is repeated to verify that duplicates are not counted.
+			iter := Map(Range(0, 2*n, 1), func(i int) int { return i % n })
+			result := ApproxCountDistinct(iter, hash)
+			if e := math.Abs(float64(result)-float64(n)) / float64(n); e > 0.03 {
+				t.Fatalf("Unexpected: %v, relative error %v", result, e)
+			}
+		})
+	}
+	t.Run("empty", func(t *testing.T) {
+		result := ApproxCountDistinct(Empty[int](), hash)
+		if result != 0 {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
 func testCounterImplementation[T any](t *testing.T, iter Iterator[T], expectedCount int) {
 	t.Run(fmt.Sprintf("count %d", expectedCount), func(t *testing.T) {
 		counter, ok := iter.(Counter[T])
@@ -91,6 +334,24 @@ func testCounterImplementation[T any](t *testing.T, iter Iterator[T], expectedCo
 	})
 }
 
+func TestDataDependentCount(t *testing.T) {
+	// Operations of which the count depends on the items must not implement Counter, as a
+	// specialized implementation would skip the functions that determine the count.
+	even := func(i int) bool { return i%2 == 0 }
+	evenMap := func(i int) (int, bool) { return i, i%2 == 0 }
+	for name, iter := range map[string]Iterator[int]{
+		"Filter":        Filter(Range(0, 10, 1), even),
+		"FilterMap":     FilterMap(Range(0, 10, 1), evenMap),
+		"MapWhile":      MapWhile(Range(0, 10, 1), evenMap),
+		"FilterIndexed": FilterIndexed(Range(0, 10, 1), func(_, i int) bool { return even(i) }),
+		"OfType":        OfType[int](Map(Range(0, 10, 1), func(i int) any { return i })),
+	} {
+		if _, ok := iter.(Counter[int]); ok {
+			t.Fatalf("%s implements Counter", name)
+		}
+	}
+}
+
 func TestSum(t *testing.T) {
 	iter := FromSlice([]int{1, 2, 3})
 	result := Sum(iter)
@@ -99,6 +360,60 @@ func TestSum(t *testing.T) {
 	}
 }
 
+func TestSumKahan(t *testing.T) {
+	items := append([]float64{1}, ToSlice(Take(Repeat(1e-16), 10000))...)
+	if naive := Sum(FromSlice(items)); naive != 1 {
+		t.Fatalf("Expected naive summation to lose precision: %v", naive)
+	}
+	result := SumKahan(FromSlice(items))
+	if math.Abs(result-(1+1e-12)) > 1e-15 {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestAverage(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		val, ok := Average(Empty[int]())
+		if ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("numbers", func(t *testing.T) {
+		val, ok := Average(FromSlice([]int{1, 2, 3, 4}))
+		if !ok || val != 2.5 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+	})
+}
+
+func TestVariance(t *testing.T) {
+	items := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	t.Run("empty", func(t *testing.T) {
+		if val, ok := Variance(Empty[int]()); ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+		if val, ok := SampleVariance(Once(1)); ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("population", func(t *testing.T) {
+		if val, ok := Variance(FromSlice(items)); !ok || val != 4 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+		if val, ok := StdDev(FromSlice(items)); !ok || val != 2 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+	})
+	t.Run("sample", func(t *testing.T) {
+		if val, ok := SampleVariance(FromSlice(items)); !ok || math.Abs(val-32.0/7) > 1e-12 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+		if val, ok := SampleStdDev(FromSlice(items)); !ok || math.Abs(val-math.Sqrt(32.0/7)) > 1e-12 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+	})
+}
+
 func TestMin(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		iter := Empty[int]()
@@ -133,6 +448,56 @@ func TestMax(t *testing.T) {
 	})
 }
 
+func TestMinIndex(t *testing.T) {
+	if index, val, ok := MinIndex(Empty[int]()); ok {
+		t.Fatalf("Unexpected: %v, %v", index, val)
+	}
+	index, val, ok := MinIndex(FromSlice([]int{4, 1, 5, 1}))
+	if !ok || index != 1 || val != 1 {
+		t.Fatalf("Unexpected: %v, %v, %v", index, val, ok)
+	}
+}
+
+func TestMaxIndex(t *testing.T) {
+	if index, val, ok := MaxIndex(Empty[int]()); ok {
+		t.Fatalf("Unexpected: %v, %v", index, val)
+	}
+	index, val, ok := MaxIndex(FromSlice([]int{4, 7, 5, 7}))
+	if !ok || index != 1 || val != 7 {
+		t.Fatalf("Unexpected: %v, %v, %v", index, val, ok)
+	}
+}
+
+func TestAllMin(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		result := AllMin(Empty[int]())
+		if !reflect.DeepEqual(result, []int{}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("ties", func(t *testing.T) {
+		result := AllMin(FromSlice([]int{3, 1, 4, 1, 5}))
+		if !reflect.DeepEqual(result, []int{1, 1}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestAllMax(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		result := AllMax(Empty[int]())
+		if !reflect.DeepEqual(result, []int{}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("ties", func(t *testing.T) {
+		result := AllMax(FromSlice([]int{5, 3, 5, 1, 5}))
+		if !reflect.DeepEqual(result, []int{5, 5, 5}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
 func TestJoin(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		iter := Empty[string]()
@@ -149,3 +514,733 @@ func TestJoin(t *testing.T) {
 		}
 	})
 }
+
+func TestFirstOr(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		val := FirstOr(Empty[int](), 42)
+		if val != 42 {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("numbers", func(t *testing.T) {
+		val := FirstOr(FromSlice([]int{1, 2, 3}), 42)
+		if val != 1 {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+}
+
+func TestElementAtOr(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		val := ElementAtOr(Empty[int](), 0, 42)
+		if val != 42 {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("in range", func(t *testing.T) {
+		val := ElementAtOr(FromSlice([]int{1, 2, 3}), 2, 42)
+		if val != 3 {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("out of range", func(t *testing.T) {
+		val := ElementAtOr(FromSlice([]int{1, 2, 3}), 3, 42)
+		if val != 42 {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+}
+
+func TestDefaultIfEmpty(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		iter := DefaultIfEmpty(Empty[int](), 42)
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []int{42}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("numbers", func(t *testing.T) {
+		iter := DefaultIfEmpty(FromSlice([]int{1, 2, 3}), 42)
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []int{1, 2, 3}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestInterleave(t *testing.T) {
+	t.Run("uneven", func(t *testing.T) {
+		iter := Interleave(FromSlice([]int{1, 2, 3}), FromSlice([]int{10, 20}))
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []int{1, 10, 2, 20, 3}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("single", func(t *testing.T) {
+		iter := Interleave(FromSlice([]int{1, 2, 3}))
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []int{1, 2, 3}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("none", func(t *testing.T) {
+		iter := Interleave[int]()
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []int{}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	source := Map(Range(0, 5, 1), func(i int) int {
+		calls++
+		return i
+	})
+	factory := Memoize(source)
+	iter0, iter1 := factory(), factory()
+	iter0.Next() // Interleave the consumption of both iterators.
+	result0 := ToSlice(iter1)
+	result1 := append([]int{0}, ToSlice(iter0)...)
+	if !reflect.DeepEqual(result0, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("Unexpected: %v", result0)
+	}
+	if !reflect.DeepEqual(result0, result1) {
+		t.Fatalf("Unexpected: %v", result1)
+	}
+	if calls != 5 {
+		t.Fatalf("Unexpected number of calls: %v", calls)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	reported := []int{}
+	iter := Progress(Range(0, 10, 1), 3, func(count int) {
+		reported = append(reported, count)
+	})
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, ToSlice(Range(0, 10, 1))) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if !reflect.DeepEqual(reported, []int{3, 6, 9}) {
+		t.Fatalf("Unexpected reports: %v", reported)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	t.Run("partial", func(t *testing.T) {
+		head, rest := Span(FromSlice([]int{1, 2, 5, 3, 6}), func(i int) bool { return i < 4 })
+		if !reflect.DeepEqual(head, []int{1, 2}) {
+			t.Fatalf("Unexpected head: %v", head)
+		}
+		if result := ToSlice(rest); !reflect.DeepEqual(result, []int{5, 3, 6}) {
+			t.Fatalf("Unexpected rest: %v", result)
+		}
+	})
+	t.Run("all pass", func(t *testing.T) {
+		head, rest := Span(FromSlice([]int{1, 2, 3}), func(i int) bool { return i < 4 })
+		if !reflect.DeepEqual(head, []int{1, 2, 3}) {
+			t.Fatalf("Unexpected head: %v", head)
+		}
+		if result := ToSlice(rest); !reflect.DeepEqual(result, []int{}) {
+			t.Fatalf("Unexpected rest: %v", result)
+		}
+	})
+}
+
+func TestSplitAt(t *testing.T) {
+	t.Run("in range", func(t *testing.T) {
+		head, rest := SplitAt(Range(0, 5, 1), 2)
+		if !reflect.DeepEqual(head, []int{0, 1}) {
+			t.Fatalf("Unexpected head: %v", head)
+		}
+		if result := ToSlice(rest); !reflect.DeepEqual(result, []int{2, 3, 4}) {
+			t.Fatalf("Unexpected rest: %v", result)
+		}
+	})
+	t.Run("beyond length", func(t *testing.T) {
+		head, rest := SplitAt(Range(0, 3, 1), 5)
+		if !reflect.DeepEqual(head, []int{0, 1, 2}) {
+			t.Fatalf("Unexpected head: %v", head)
+		}
+		if result := ToSlice(rest); !reflect.DeepEqual(result, []int{}) {
+			t.Fatalf("Unexpected rest: %v", result)
+		}
+	})
+	t.Run("zero", func(t *testing.T) {
+		head, rest := SplitAt(Range(0, 3, 1), 0)
+		if !reflect.DeepEqual(head, []int{}) {
+			t.Fatalf("Unexpected head: %v", head)
+		}
+		if result := ToSlice(rest); !reflect.DeepEqual(result, []int{0, 1, 2}) {
+			t.Fatalf("Unexpected rest: %v", result)
+		}
+	})
+}
+
+func TestDifferences(t *testing.T) {
+	t.Run("increasing", func(t *testing.T) {
+		result := ToSlice(Differences(FromSlice([]int{1, 4, 9, 16})))
+		if !reflect.DeepEqual(result, []int{3, 5, 7}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("decreasing", func(t *testing.T) {
+		result := ToSlice(Differences(FromSlice([]int{10, 7, 7, 1})))
+		if !reflect.DeepEqual(result, []int{-3, 0, -6}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("single", func(t *testing.T) {
+		result := ToSlice(Differences(FromSlice([]int{1})))
+		if !reflect.DeepEqual(result, []int{}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+
+	testCounterImplementation(t, Differences(Range(0, 5, 1)), 4)
+	testCounterImplementation(t, Differences(Range(0, 0, 1)), 0)
+}
+
+func TestMovingAverage(t *testing.T) {
+	t.Run("numbers", func(t *testing.T) {
+		result := ToSlice(MovingAverage(FromSlice([]int{2, 4, 6, 8, 1}), 3))
+		if !reflect.DeepEqual(result, []float64{4, 6, 5}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("too short", func(t *testing.T) {
+		result := ToSlice(MovingAverage(FromSlice([]int{2, 4}), 3))
+		if !reflect.DeepEqual(result, []float64{}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestHistogram(t *testing.T) {
+	t.Run("uniform", func(t *testing.T) {
+		result := Histogram(Range(0, 100, 1), 0, 100, 4)
+		if !reflect.DeepEqual(result, []int{25, 25, 25, 25}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("edges", func(t *testing.T) {
+		result := Histogram(FromSlice([]float64{-1, 0, 2.5, 5, 7.5, 10, 11}), 0, 10, 4)
+		if !reflect.DeepEqual(result, []int{1, 1, 1, 2}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("range overflows type", func(t *testing.T) {
+		result := Histogram(FromSlice([]int8{-100, 0, 50, 100}), -100, 100, 4)
+		if !reflect.DeepEqual(result, []int{1, 0, 1, 2}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("NaN", func(t *testing.T) {
+		result := Histogram(FromSlice([]float64{math.NaN(), 1, math.NaN()}), 0, 10, 2)
+		if !reflect.DeepEqual(result, []int{1, 0}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("panic on invalid range", func(t *testing.T) {
+		for _, r := range [][2]float64{{10, 0}, {0, 0}, {math.NaN(), 10}, {0, math.NaN()}} {
+			var err interface{}
+			func() {
+				defer func() { err = recover() }()
+				Histogram(FromSlice([]float64{1}), r[0], r[1], 2)
+			}()
+			if err == nil {
+				t.Fatalf("Expected panic for %v", r)
+			}
+		}
+	})
+}
+
+func TestMedian(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		val, ok := Median(Empty[int]())
+		if ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("odd", func(t *testing.T) {
+		val, ok := Median(FromSlice([]int{5, 1, 3}))
+		if !ok || val != 3 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+	})
+	t.Run("even", func(t *testing.T) {
+		val, ok := Median(FromSlice([]int{4, 1, 3, 2}))
+		if !ok || val != 2 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+	})
+}
+
+func TestMedianFloat(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		val, ok := MedianFloat(Empty[int]())
+		if ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("odd", func(t *testing.T) {
+		val, ok := MedianFloat(FromSlice([]int{5, 1, 3}))
+		if !ok || val != 3 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+	})
+	t.Run("even", func(t *testing.T) {
+		val, ok := MedianFloat(FromSlice([]int{4, 1, 3, 2}))
+		if !ok || val != 2.5 {
+			t.Fatalf("Unexpected: %v, %v", val, ok)
+		}
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	items := []int{15, 20, 35, 40, 50}
+	for _, tc := range []struct {
+		p      float64
+		expect int
+	}{
+		{p: 0, expect: 15},
+		{p: 50, expect: 35},
+		{p: 100, expect: 50},
+	} {
+		val, ok := Percentile(FromSlice(items), tc.p)
+		if !ok || val != tc.expect {
+			t.Fatalf("Unexpected for p%v: %v, %v", tc.p, val, ok)
+		}
+	}
+	t.Run("empty", func(t *testing.T) {
+		val, ok := Percentile(Empty[int](), 50)
+		if ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("panic on out of range", func(t *testing.T) {
+		for _, p := range []float64{-1, 101, math.NaN()} {
+			var err interface{}
+			func() {
+				defer func() { err = recover() }()
+				Percentile(FromSlice(items), p)
+			}()
+			if err == nil {
+				t.Fatalf("Expected panic for %v", p)
+			}
+		}
+	})
+}
+
+func TestChunk(t *testing.T) {
+	result := ToSlice(Chunk(Range(1, 8, 1), 3))
+	if !reflect.DeepEqual(result, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestChunkReuse(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		var result [][]int
+		iter := ChunkReuse(Range(1, 8, 1), 3)
+		for chunk, ok := iter.Next(); ok; chunk, ok = iter.Next() {
+			result = append(result, append([]int{}, chunk...))
+		}
+		if !reflect.DeepEqual(result, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("buffer overwritten", func(t *testing.T) {
+		iter := ChunkReuse(Range(1, 5, 1), 2)
+		first, _ := iter.Next()
+		second, _ := iter.Next()
+		if !reflect.DeepEqual(second, []int{3, 4}) {
+			t.Fatalf("Unexpected: %v", second)
+		}
+		if !reflect.DeepEqual(first, []int{3, 4}) {
+			t.Fatalf("Expected first chunk to be overwritten, got %v", first)
+		}
+	})
+}
+
+func TestChunkReduce(t *testing.T) {
+	iter := ChunkReduce(Range(1, 8, 1), 3, func(accum, item int) int {
+		return accum + item
+	}, func() int { return 0 })
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{6, 15, 7}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestGroupConsecutive(t *testing.T) {
+	iter := GroupConsecutive(FromSlice([]string{"a1", "a2", "b1", "a3"}), func(s string) byte {
+		return s[0]
+	})
+	result := ToSlice(iter)
+	expect := []MapEntry[byte, []string]{
+		{Key: 'a', Val: []string{"a1", "a2"}},
+		{Key: 'b', Val: []string{"b1"}},
+		{Key: 'a', Val: []string{"a3"}},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestRunLength(t *testing.T) {
+	iter := RunLength(FromSlice([]string{"a", "a", "a", "b", "c", "c"}))
+	result := ToSlice(iter)
+	expect := []RunLengthEntry[string]{
+		{Value: "a", Count: 3},
+		{Value: "b", Count: 1},
+		{Value: "c", Count: 2},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestZipWith(t *testing.T) {
+	add := func(a, b int) int { return a + b }
+	iter := ZipWith(FromSlice([]int{1, 2, 3}), FromSlice([]int{10, 20}), add)
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{11, 22}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, ZipWith(Range(0, 3, 1), Range(0, 2, 1), add), 2)
+	testCounterImplementation(t, ZipWith(Range(0, 2, 1), Range(0, 3, 1), add), 2)
+	testCounterImplementation(t, ZipWith(Range(0, 3, 1), Repeat(1), add), 3)
+	testCounterImplementation(t, ZipWith(Repeat(1), Range(0, 3, 1), add), 3)
+	evens := Filter(Range(0, 6, 1), func(i int) bool { return i%2 == 0 })
+	testCounterImplementation(t, ZipWith(Repeat(1), evens, add), 3)
+	id := func(i int) int { return i }
+	testCounterImplementation(t, ZipWith(Map(Repeat(1), id), FromSlice([]int{1, 2, 3}), add), 3)
+}
+
+func TestZipLongest(t *testing.T) {
+	t.Run("left longer", func(t *testing.T) {
+		iter := ZipLongest(FromSlice([]int{1, 2, 3}), FromSlice([]string{"x"}), -1, "-")
+		result := ToSlice(iter)
+		expect := []Pair[int, string]{{A: 1, B: "x"}, {A: 2, B: "-"}, {A: 3, B: "-"}}
+		if !reflect.DeepEqual(result, expect) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("right longer", func(t *testing.T) {
+		iter := ZipLongest(FromSlice([]int{1}), FromSlice([]string{"x", "y"}), -1, "-")
+		result := ToSlice(iter)
+		expect := []Pair[int, string]{{A: 1, B: "x"}, {A: -1, B: "y"}}
+		if !reflect.DeepEqual(result, expect) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+
+	testCounterImplementation(t, ZipLongest(Range(0, 3, 1), Range(0, 2, 1), 0, 0), 3)
+}
+
+func TestPosition(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		iter := FromSlice([]int{1, 3, 4, 5, 6})
+		index, ok := Position(iter, func(i int) bool { return i%2 == 0 })
+		if !ok || index != 2 {
+			t.Fatalf("Unexpected: %v, %v", index, ok)
+		}
+		if result := ToSlice(iter); !reflect.DeepEqual(result, []int{5, 6}) {
+			t.Fatalf("Unexpected remainder: %v", result)
+		}
+	})
+	t.Run("no match", func(t *testing.T) {
+		index, ok := Position(FromSlice([]int{1, 3, 5}), func(i int) bool { return i%2 == 0 })
+		if ok {
+			t.Fatalf("Unexpected: %v", index)
+		}
+	})
+}
+
+func TestFuse(t *testing.T) {
+	iter := Fuse[int](&flakyIterator{})
+	if val, ok := iter.Next(); !ok || val != 1 {
+		t.Fatalf("Unexpected: %v, %v", val, ok)
+	}
+	for i := 0; i < 3; i++ {
+		if val, ok := iter.Next(); ok {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	}
+
+	testCounterImplementation(t, Fuse(Range(0, 3, 1)), 3)
+}
+
+// flakyIterator violates the Iterator contract by alternating between returning an item and
+// returning false.
+type flakyIterator struct {
+	calls int
+}
+
+func (iter *flakyIterator) Next() (int, bool) {
+	iter.calls++
+	return iter.calls, iter.calls%2 == 1
+}
+
+func TestJoinFunc(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		val := JoinFunc(Empty[int](), ", ", strconv.Itoa)
+		if val != "" {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("hex", func(t *testing.T) {
+		val := JoinFunc(FromSlice([]int{10, 255, 4096}), ",", func(i int) string {
+			return fmt.Sprintf("%#x", i)
+		})
+		if val != "0xa,0xff,0x1000" {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+}
+
+func TestJoinWrap(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		val := JoinWrap(Empty[string](), "[", ", ", "]")
+		if val != "[]" {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+	t.Run("strings", func(t *testing.T) {
+		val := JoinWrap(FromSlice([]string{"a", "b", "c"}), "[", ", ", "]")
+		if val != "[a, b, c]" {
+			t.Fatalf("Unexpected: %v", val)
+		}
+	})
+}
+
+func BenchmarkJoin(b *testing.B) {
+	items := ToSlice(Map(Range(0, 5000, 1), func(i int) string { return fmt.Sprint(i) }))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Join(FromSlice(items), ", ")
+	}
+}
+
+func TestAggregateWindow(t *testing.T) {
+	max := func(window []int) int {
+		val, _ := Max(FromSlice(window))
+		return val
+	}
+	iter := AggregateWindow(FromSlice([]int{1, 3, 2, 5, 4, 1}), 3, max)
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{3, 5, 5, 5}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, AggregateWindow(Range(0, 6, 1), 3, max), 4)
+	testCounterImplementation(t, AggregateWindow(Range(0, 2, 1), 3, max), 0)
+
+	countIter := AggregateWindow(Range(0, 6, 1), 3, max)
+	countIter.Next() // Test whether the buffered items are included.
+	testCounterImplementation(t, countIter, 3)
+}
+
+func TestPrepend(t *testing.T) {
+	result := ToSlice(Prepend(0, FromSlice([]int{1, 2})))
+	if !reflect.DeepEqual(result, []int{0, 1, 2}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, Prepend(0, FromSlice([]int{1, 2})), 3)
+}
+
+func TestAppend(t *testing.T) {
+	result := ToSlice(Append(FromSlice([]int{1, 2}), 3))
+	if !reflect.DeepEqual(result, []int{1, 2, 3}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, Append(FromSlice([]int{1, 2}), 3), 3)
+}
+
+func TestTeeN(t *testing.T) {
+	t.Run("sequential", func(t *testing.T) {
+		iters := TeeN(Range(0, 5, 1), 3)
+		iters[0].Next() // Let the branches diverge.
+		for i, iter := range iters[1:] {
+			if result := ToSlice(iter); !reflect.DeepEqual(result, []int{0, 1, 2, 3, 4}) {
+				t.Fatalf("Unexpected for branch %d: %v", i+1, result)
+			}
+		}
+		if result := ToSlice(iters[0]); !reflect.DeepEqual(result, []int{1, 2, 3, 4}) {
+			t.Fatalf("Unexpected for branch 0: %v", result)
+		}
+	})
+	t.Run("buffer is released", func(t *testing.T) {
+		iters := TeeN(Range(0, 100, 1), 2)
+		for i := 0; i < 100; i++ {
+			iters[0].Next()
+			iters[1].Next()
+		}
+		buffer := iters[0].(*teeIterator[int]).buffer
+		if len(buffer.items) != 0 {
+			t.Fatalf("Unexpected buffered items: %v", len(buffer.items))
+		}
+	})
+}
+
+func TestPeekable(t *testing.T) {
+	iter := Peekable(FromSlice([]int{1, 2}))
+	if val, ok := iter.Peek(); !ok || val != 1 {
+		t.Fatalf("Unexpected: %v, %v", val, ok)
+	}
+	if val, ok := iter.Peek(); !ok || val != 1 {
+		t.Fatalf("Unexpected on second peek: %v, %v", val, ok)
+	}
+	if result := ToSlice[int](iter); !reflect.DeepEqual(result, []int{1, 2}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if val, ok := iter.Peek(); ok {
+		t.Fatalf("Unexpected: %v", val)
+	}
+
+	countIter := Peekable(FromSlice([]int{1, 2, 3}))
+	countIter.Peek() // Test whether the peeked item is included.
+	testCounterImplementation[int](t, countIter, 3)
+}
+
+func TestConsumeWhile(t *testing.T) {
+	isDigit := func(r rune) bool { return '0' <= r && r <= '9' }
+	iter := Peekable(FromRunes("123+45"))
+	if result := ConsumeWhile(iter, isDigit); !reflect.DeepEqual(result, []rune("123")) {
+		t.Fatalf("Unexpected: %q", result)
+	}
+	if val, ok := iter.Peek(); !ok || val != '+' {
+		t.Fatalf("Unexpected boundary: %q, %v", val, ok)
+	}
+	iter.Next()
+	if result := ConsumeWhile(iter, isDigit); !reflect.DeepEqual(result, []rune("45")) {
+		t.Fatalf("Unexpected: %q", result)
+	}
+}
+
+func TestChunkByWeight(t *testing.T) {
+	iter := ChunkByWeight(FromSlice([]int{3, 4, 2, 12, 5, 5, 1}), 10, func(i int) int { return i })
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, [][]int{{3, 4, 2}, {12}, {5, 5}, {1}}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestDistinctBounded(t *testing.T) {
+	t.Run("within window", func(t *testing.T) {
+		iter := DistinctBounded(FromSlice([]int{1, 2, 1, 2, 3, 3}), 3)
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []int{1, 2, 3}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("beyond window", func(t *testing.T) {
+		iter := DistinctBounded(FromSlice([]int{1, 2, 3, 1, 3}), 2)
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []int{1, 2, 3, 1}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestFlatMapSlice(t *testing.T) {
+	iter := FlatMapSlice(Range(0, 6, 1), func(i int) ([]int, bool) {
+		switch i % 3 {
+		case 0:
+			return nil, false
+		case 1:
+			return []int{}, true
+		default:
+			return []int{i, i * 10}, true
+		}
+	})
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{2, 20, 5, 50}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func BenchmarkFlatMapSparse(b *testing.B) {
+	// Only one in a hundred items produces any output.
+	items := ToSlice(Range(0, 10000, 1))
+	b.Run("FlatMapSlice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Drain(FlatMapSlice(FromSlice(items), func(i int) ([]int, bool) {
+				if i%100 != 0 {
+					return nil, false
+				}
+				return []int{i, i}, true
+			}))
+		}
+	})
+	b.Run("Flatten", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Drain(Flatten(Map(FromSlice(items), func(i int) Iterator[int] {
+				if i%100 != 0 {
+					return Empty[int]()
+				}
+				return FromSlice([]int{i, i})
+			})))
+		}
+	})
+}
+
+func TestFlattenDeep(t *testing.T) {
+	nested := []any{[]any{1, []int{2, 3}}, 4}
+	t.Run("depth 2", func(t *testing.T) {
+		result := ToSlice(FlattenDeep[int](FromSlice(nested), 2))
+		if !reflect.DeepEqual(result, []int{1, 2, 3, 4}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("depth 1", func(t *testing.T) {
+		result := ToSlice(FlattenDeep[any](FromSlice(nested), 1))
+		if !reflect.DeepEqual(result, []any{1, []int{2, 3}, 4}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestPipe(t *testing.T) {
+	double := func(iter Iterator[int]) Iterator[int] {
+		return Map(iter, func(i int) int { return i * 2 })
+	}
+	skipSmall := func(iter Iterator[int]) Iterator[int] {
+		return Filter(iter, func(i int) bool { return i > 4 })
+	}
+	firstThree := func(iter Iterator[int]) Iterator[int] {
+		return Take(iter, 3)
+	}
+	result := ToSlice(Pipe(Range(0, 10, 1), double, skipSmall, firstThree))
+	nested := ToSlice(firstThree(skipSmall(double(Range(0, 10, 1)))))
+	if !reflect.DeepEqual(result, []int{6, 8, 10}) || !reflect.DeepEqual(result, nested) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+func TestThen(t *testing.T) {
+	mapToString := func(iter Iterator[int]) Iterator[string] {
+		return Map(iter, func(i int) string {
+			if i%2 == 0 {
+				return ""
+			}
+			return strconv.Itoa(i)
+		})
+	}
+	filterNonEmpty := func(iter Iterator[string]) Iterator[string] {
+		return Filter(iter, func(s string) bool { return s != "" })
+	}
+	result := ToSlice(Then(Then(Range(0, 6, 1), mapToString), filterNonEmpty))
+	if !reflect.DeepEqual(result, []string{"1", "3", "5"}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
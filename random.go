@@ -0,0 +1,74 @@
+package iterator
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Sample selects k items uniformly at random from the iterator using reservoir sampling. This
+// requires only a single pass and memory for k items. If the iterator has fewer than k items, all
+// of them are returned.
+//
+// Sample panics if k is negative.
+func Sample[T any](from Iterator[T], k int, rng *rand.Rand) []T {
+	if k < 0 {
+		panic("Sample: k may not be negative")
+	}
+	reservoir := make([]T, 0, k)
+	i := 0
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		if i < k {
+			reservoir = append(reservoir, item)
+		} else if j := rng.Intn(i + 1); j < k {
+			reservoir[j] = item
+		}
+		i++
+	}
+	return reservoir
+}
+
+// WeightedSample selects k items at random from the iterator, where the chance of an item being
+// selected is proportional to the weight returned by weightFunc. It uses the A-Res algorithm, which
+// requires only a single pass and memory for k items. Items with a weight that is not positive are
+// never selected. If the iterator has fewer than k eligible items, all of them are returned.
+//
+// WeightedSample panics if k is negative.
+func WeightedSample[T any](from Iterator[T], k int, weightFunc func(T) float64, rng *rand.Rand) []T {
+	if k < 0 {
+		panic("WeightedSample: k may not be negative")
+	}
+	reservoir := make([]T, 0, k)
+	keys := make([]float64, 0, k)
+	minIndex := 0
+	for item, ok := from.Next(); ok; item, ok = from.Next() {
+		w := weightFunc(item)
+		if w <= 0 {
+			continue
+		}
+		key := math.Pow(rng.Float64(), 1/w)
+		if len(reservoir) < k {
+			reservoir = append(reservoir, item)
+			keys = append(keys, key)
+		} else if k > 0 && key > keys[minIndex] {
+			reservoir[minIndex], keys[minIndex] = item, key
+		} else {
+			continue
+		}
+		for i := range keys {
+			if keys[i] < keys[minIndex] {
+				minIndex = i
+			}
+		}
+	}
+	return reservoir
+}
+
+// Shuffle collects all items from the iterator and returns an iterator over them in a random order
+// determined by the specified random number generator. The source is consumed immediately.
+func Shuffle[T any](from Iterator[T], rng *rand.Rand) Iterator[T] {
+	items := ToSlice(from)
+	rng.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	return FromSlice(items)
+}
@@ -0,0 +1,84 @@
+package iterator
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	t.Run("seeded", func(t *testing.T) {
+		result := Sample(Range(0, 100, 1), 3, rand.New(rand.NewSource(1)))
+		if !reflect.DeepEqual(result, []int{6, 48, 22}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("fewer than k", func(t *testing.T) {
+		result := Sample(Range(0, 2, 1), 3, rand.New(rand.NewSource(1)))
+		if !reflect.DeepEqual(result, []int{0, 1}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("panic on negative k", func(t *testing.T) {
+		var err interface{}
+		func() {
+			defer func() { err = recover() }()
+			Sample(Range(0, 2, 1), -1, rand.New(rand.NewSource(1)))
+		}()
+		if err == nil {
+			t.Fatalf("Expected panic")
+		}
+	})
+}
+
+func TestWeightedSample(t *testing.T) {
+	t.Run("heavy item", func(t *testing.T) {
+		weight := func(i int) float64 {
+			if i == 42 {
+				return 1e9
+			}
+			return 1e-3
+		}
+		for seed := int64(0); seed < 20; seed++ {
+			result := WeightedSample(Range(0, 100, 1), 1, weight, rand.New(rand.NewSource(seed)))
+			if !reflect.DeepEqual(result, []int{42}) {
+				t.Fatalf("Unexpected: %v", result)
+			}
+		}
+	})
+	t.Run("fewer than k", func(t *testing.T) {
+		weight := func(i int) float64 { return float64(i) }
+		result := WeightedSample(Range(0, 3, 1), 3, weight, rand.New(rand.NewSource(1)))
+		if !reflect.DeepEqual(result, []int{1, 2}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("panic on negative k", func(t *testing.T) {
+		weight := func(i int) float64 { return 1 }
+		var err interface{}
+		func() {
+			defer func() { err = recover() }()
+			WeightedSample(Range(0, 2, 1), -1, weight, rand.New(rand.NewSource(1)))
+		}()
+		if err == nil {
+			t.Fatalf("Expected panic")
+		}
+	})
+}
+
+func TestShuffle(t *testing.T) {
+	result := ToSlice(Shuffle(Range(0, 10, 1), rand.New(rand.NewSource(1))))
+	again := ToSlice(Shuffle(Range(0, 10, 1), rand.New(rand.NewSource(1))))
+	if !reflect.DeepEqual(result, again) {
+		t.Fatalf("Unexpected different orders: %v, %v", result, again)
+	}
+	if reflect.DeepEqual(result, ToSlice(Range(0, 10, 1))) {
+		t.Fatalf("Unexpected unshuffled: %v", result)
+	}
+	sorted := append([]int{}, result...)
+	sort.Ints(sorted)
+	if !reflect.DeepEqual(sorted, ToSlice(Range(0, 10, 1))) {
+		t.Fatalf("Unexpected items: %v", result)
+	}
+}
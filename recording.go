@@ -0,0 +1,33 @@
+package iterator
+
+// RecordingIterator wraps an iterator and records the items it returns and the number of times
+// Next was called. It is intended to aid in testing custom iterators and operations.
+type RecordingIterator[T any] struct {
+	from   Iterator[T]
+	values []T
+	calls  int
+}
+
+// Record wraps the specified iterator into a RecordingIterator.
+func Record[T any](from Iterator[T]) *RecordingIterator[T] {
+	return &RecordingIterator[T]{from: from, values: []T{}}
+}
+
+func (iter *RecordingIterator[T]) Next() (T, bool) {
+	iter.calls++
+	item, ok := iter.from.Next()
+	if ok {
+		iter.values = append(iter.values, item)
+	}
+	return item, ok
+}
+
+// Values returns the items that were returned by Next so far.
+func (iter *RecordingIterator[T]) Values() []T {
+	return iter.values
+}
+
+// Calls returns the number of times Next was called, including calls that returned false.
+func (iter *RecordingIterator[T]) Calls() int {
+	return iter.calls
+}
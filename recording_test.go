@@ -0,0 +1,25 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordingIterator(t *testing.T) {
+	rec := Record(Range(0, 10, 1))
+	result := ToSlice(Take[int](rec, 3))
+	if !reflect.DeepEqual(result, []int{0, 1, 2}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if !reflect.DeepEqual(rec.Values(), []int{0, 1, 2}) {
+		t.Fatalf("Unexpected values: %v", rec.Values())
+	}
+	if rec.Calls() != 3 {
+		t.Fatalf("Unexpected calls: %v", rec.Calls())
+	}
+
+	Drain[int](rec)
+	if len(rec.Values()) != 10 || rec.Calls() != 11 {
+		t.Fatalf("Unexpected after draining: %v, %v", rec.Values(), rec.Calls())
+	}
+}
@@ -0,0 +1,116 @@
+package iterator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FromRunes creates a new iterator which returns the Unicode code points of the UTF-8 encoded
+// string. Invalid encodings are returned as utf8.RuneError, like a range loop over a string does.
+//
+// The returned iterator implements Seekable, where the index is counted in runes.
+func FromRunes(s string) Iterator[rune] {
+	return &runeIterator{s: s}
+}
+
+type runeIterator struct {
+	s      string
+	offset int
+}
+
+func (iter *runeIterator) Next() (rune, bool) {
+	if iter.offset >= len(iter.s) {
+		return 0, false
+	}
+	r, size := utf8.DecodeRuneInString(iter.s[iter.offset:])
+	iter.offset += size
+	return r, true
+}
+
+func (iter *runeIterator) Count() int {
+	count := utf8.RuneCountInString(iter.s[iter.offset:])
+	iter.offset = len(iter.s)
+	return count
+}
+
+// Seek moves to the rune at the specified index. Because UTF-8 has a variable width, this takes time
+// proportional to the index.
+func (iter *runeIterator) Seek(index int) {
+	if index < 0 {
+		panic("Seek: index may not be negative")
+	}
+	iter.offset = 0
+	for ; index > 0 && iter.offset < len(iter.s); index-- {
+		_, size := utf8.DecodeRuneInString(iter.s[iter.offset:])
+		iter.offset += size
+	}
+}
+
+// FromBytes creates a new iterator which returns all bytes from the slice. Like FromSlice, the
+// returned iterator implements Seekable.
+func FromBytes(b []byte) Iterator[byte] {
+	return FromSlice(b)
+}
+
+// Fields creates a new iterator which lazily returns the substrings of s that are separated by one
+// or more whitespace characters, like strings.Fields does.
+func Fields(s string) Iterator[string] {
+	return &fieldsIterator{s: s}
+}
+
+type fieldsIterator struct {
+	s string
+}
+
+func (iter *fieldsIterator) Next() (string, bool) {
+	start := strings.IndexFunc(iter.s, func(r rune) bool { return !unicode.IsSpace(r) })
+	if start < 0 {
+		iter.s = ""
+		return "", false
+	}
+	iter.s = iter.s[start:]
+	end := strings.IndexFunc(iter.s, unicode.IsSpace)
+	if end < 0 {
+		end = len(iter.s)
+	}
+	field := iter.s[:end]
+	iter.s = iter.s[end:]
+	return field, true
+}
+
+// SplitString creates a new iterator which lazily returns the substrings of s separated by sep,
+// with the same semantics as strings.Split.
+func SplitString(s, sep string) Iterator[string] {
+	return &splitStringIterator{s: s, sep: sep}
+}
+
+type splitStringIterator struct {
+	s, sep string
+	done   bool
+}
+
+func (iter *splitStringIterator) Next() (string, bool) {
+	if iter.done {
+		return "", false
+	}
+	if iter.sep == "" {
+		// Like strings.Split, an empty separator splits after each UTF-8 sequence.
+		if iter.s == "" {
+			iter.done = true
+			return "", false
+		}
+		_, size := utf8.DecodeRuneInString(iter.s)
+		field := iter.s[:size]
+		iter.s = iter.s[size:]
+		return field, true
+	}
+	i := strings.Index(iter.s, iter.sep)
+	if i < 0 {
+		iter.done = true
+		return iter.s, true
+	}
+	field := iter.s[:i]
+	iter.s = iter.s[i+len(iter.sep):]
+	return field, true
+}
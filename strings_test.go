@@ -0,0 +1,76 @@
+package iterator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromRunes(t *testing.T) {
+	t.Run("multi-byte", func(t *testing.T) {
+		iter := FromRunes("a€😀b")
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, []rune{'a', '€', '😀', 'b'}) {
+			t.Fatalf("Unexpected: %q", result)
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		iter := FromRunes("a€😀b").(Seekable[rune])
+		iter.Seek(2)
+		if result := ToSlice[rune](iter); !reflect.DeepEqual(result, []rune{'😀', 'b'}) {
+			t.Fatalf("Unexpected: %q", result)
+		}
+		iter.Seek(1)
+		if val, ok := iter.Next(); !ok || val != '€' {
+			t.Fatalf("Unexpected: %q, %v", val, ok)
+		}
+	})
+
+	testCounterImplementation(t, FromRunes(""), 0)
+	testCounterImplementation(t, FromRunes("a€😀b"), 4)
+}
+
+func TestFromBytes(t *testing.T) {
+	iter := FromBytes([]byte("a€"))
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []byte{'a', 0xe2, 0x82, 0xac}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+
+	testCounterImplementation(t, FromBytes([]byte("a€")), 4)
+}
+
+func TestFields(t *testing.T) {
+	for _, input := range []string{
+		"  foo bar   baz ",
+		"foo\tbar\n\tbaz",
+		"",
+		"   ",
+		"foo",
+	} {
+		result := ToSlice(Fields(input))
+		if expect := strings.Fields(input); !reflect.DeepEqual(result, expect) {
+			t.Fatalf("Unexpected for %q: %q", input, result)
+		}
+	}
+}
+
+func TestSplitString(t *testing.T) {
+	for _, tc := range []struct {
+		s, sep string
+	}{
+		{s: "a,b,c", sep: ","},
+		{s: "a,,b,", sep: ","},
+		{s: "a::b", sep: "::"},
+		{s: "abc", sep: ","},
+		{s: "", sep: ","},
+		{s: "a€b", sep: ""},
+		{s: "", sep: ""},
+	} {
+		result := ToSlice(SplitString(tc.s, tc.sep))
+		if expect := strings.Split(tc.s, tc.sep); !reflect.DeepEqual(result, expect) {
+			t.Fatalf("Unexpected for %q, %q: %q", tc.s, tc.sep, result)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package iterator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to the time based operations of this package.
+//
+// The default implementation is backed by the time package. Tests may substitute their own
+// implementation using WithClock to make time based operations deterministic.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	NewTimer(d time.Duration) Timer
+}
+
+// A Timer sends the current time on its channel once it expires, like time.Timer.
+//
+// Stop prevents the timer from firing and reports whether it was still active. Reset changes the
+// timer to expire after the specified duration, it should only be called on stopped or expired
+// timers of which the channel has been drained.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                 { return time.Now() }
+func (realClock) Sleep(d time.Duration)          { time.Sleep(d) }
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+// A TimeOption configures a time based operation.
+type TimeOption func(*timeOptions)
+
+type timeOptions struct {
+	clock Clock
+}
+
+func newTimeOptions(opts []TimeOption) timeOptions {
+	options := timeOptions{clock: realClock{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithClock sets the clock used to measure and wait for time.
+func WithClock(clock Clock) TimeOption {
+	return func(options *timeOptions) {
+		options.clock = clock
+	}
+}
+
+// BatchTimeout returns an iterator that groups the items from the specified iterator into slices.
+// A batch is returned once it holds maxSize items, or when maxWait has elapsed since the first item
+// of the batch was received, whichever comes first. A partial final batch is returned at the end of
+// the stream.
+//
+// The specified iterator is consumed from a separate goroutine so the timeout can fire while the
+// source is blocked. A valid context should be passed that cancels when the iterator chain goes out
+// of scope, this prevents the goroutine from leaking if the iterator is not fully consumed.
+//
+// BatchTimeout panics if maxSize is not positive.
+func BatchTimeout[T any](ctx context.Context, from Iterator[T], maxSize int, maxWait time.Duration, opts ...TimeOption) Iterator[[]T] {
+	if maxSize <= 0 {
+		panic("BatchTimeout: maxSize must be positive")
+	}
+	return &batchTimeoutIterator[T]{
+		ctx:     ctx,
+		from:    ToChannel(ctx, from, 0),
+		maxSize: maxSize,
+		maxWait: maxWait,
+		clock:   newTimeOptions(opts).clock,
+	}
+}
+
+type batchTimeoutIterator[T any] struct {
+	ctx     context.Context
+	from    <-chan T
+	maxSize int
+	maxWait time.Duration
+	clock   Clock
+}
+
+func (iter *batchTimeoutIterator[T]) Next() ([]T, bool) {
+	if iter.from == nil {
+		return nil, false
+	}
+	var batch []T
+	select {
+	case item, ok := <-iter.from:
+		if !ok {
+			iter.from = nil
+			return nil, false
+		}
+		batch = append(batch, item)
+	case <-iter.ctx.Done():
+		iter.from = nil
+		return nil, false
+	}
+
+	timer := iter.clock.NewTimer(iter.maxWait)
+	defer timer.Stop()
+	for len(batch) < iter.maxSize {
+		select {
+		case item, ok := <-iter.from:
+			if !ok {
+				iter.from = nil
+				return batch, true
+			}
+			batch = append(batch, item)
+		case <-timer.C():
+			return batch, true
+		case <-iter.ctx.Done():
+			iter.from = nil
+			return nil, false
+		}
+	}
+	return batch, true
+}
+
+// Throttle returns an iterator that ensures that at least the specified interval elapses between
+// each returned item by sleeping in Next as needed. The first item is returned immediately.
+func Throttle[T any](from Iterator[T], interval time.Duration, opts ...TimeOption) Iterator[T] {
+	return &throttleIterator[T]{from: from, interval: interval, clock: newTimeOptions(opts).clock}
+}
+
+type throttleIterator[T any] struct {
+	from     Iterator[T]
+	interval time.Duration
+	last     time.Time
+	clock    Clock
+}
+
+func (iter *throttleIterator[T]) Next() (T, bool) {
+	item, ok := iter.from.Next()
+	if !ok {
+		return item, false
+	}
+	if !iter.last.IsZero() {
+		if wait := iter.interval - iter.clock.Now().Sub(iter.last); wait > 0 {
+			iter.clock.Sleep(wait)
+		}
+	}
+	iter.last = iter.clock.Now()
+	return item, true
+}
+
+// Retry calls factory up to the specified number of attempts until it returns an iterator without
+// an error, sleeping for the backoff duration between failed attempts. The error of the last
+// attempt is returned if all attempts fail.
+//
+// Retry panics if attempts is not positive.
+func Retry[T any](attempts int, backoff time.Duration, factory func() (Iterator[T], error), opts ...TimeOption) (Iterator[T], error) {
+	if attempts <= 0 {
+		panic("Retry: attempts must be positive")
+	}
+	clock := newTimeOptions(opts).clock
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			clock.Sleep(backoff)
+		}
+		var iter Iterator[T]
+		if iter, err = factory(); err == nil {
+			return iter, nil
+		}
+	}
+	return nil, err
+}
+
+// ErrTimeout is reported by the iterator returned by Timeout if the source did not return an item
+// in time.
+var ErrTimeout = errors.New("iterator: timeout")
+
+// Timeout returns an iterator that gives up on the specified iterator if a single call to its Next
+// takes longer than d. In that case, false is returned and Err reports ErrTimeout.
+//
+// Each call to Next of the source is run on a separate goroutine. After a timeout the source is
+// abandoned, so at most one goroutine is left running until the blocked call returns.
+func Timeout[T any](from Iterator[T], d time.Duration, opts ...TimeOption) Fallible[T] {
+	return &timeoutIterator[T]{from: from, d: d, clock: newTimeOptions(opts).clock}
+}
+
+type timeoutIterator[T any] struct {
+	from  Iterator[T]
+	d     time.Duration
+	clock Clock
+	err   error
+}
+
+func (iter *timeoutIterator[T]) Next() (T, bool) {
+	var zero T
+	if iter.from == nil {
+		return zero, false
+	}
+	// The channel is buffered so the goroutine can always deliver its result and exit, even if
+	// nobody is waiting for it anymore.
+	result := make(chan Pair[T, bool], 1)
+	go func(from Iterator[T]) {
+		item, ok := from.Next()
+		result <- Pair[T, bool]{A: item, B: ok}
+	}(iter.from)
+
+	timer := iter.clock.NewTimer(iter.d)
+	select {
+	case r := <-result:
+		timer.Stop()
+		if !r.B {
+			iter.from = nil
+		}
+		return r.A, r.B
+	case <-timer.C():
+		iter.from = nil
+		iter.err = ErrTimeout
+		return zero, false
+	}
+}
+
+func (iter *timeoutIterator[T]) Err() error {
+	return iter.err
+}
+
+// Debounce returns an iterator that only returns an item once the specified quiet duration has
+// elapsed without the specified iterator producing a newer item. Items that are superseded within
+// the quiet duration are dropped. The last item before the end of the stream is always returned.
+//
+// The specified iterator is consumed from a separate goroutine. A valid context should be passed
+// that cancels when the iterator chain goes out of scope, this prevents the goroutine from leaking
+// if the iterator is not fully consumed.
+func Debounce[T any](ctx context.Context, from Iterator[T], quiet time.Duration, opts ...TimeOption) Iterator[T] {
+	return &debounceIterator[T]{
+		ctx:   ctx,
+		from:  ToChannel(ctx, from, 0),
+		quiet: quiet,
+		clock: newTimeOptions(opts).clock,
+	}
+}
+
+type debounceIterator[T any] struct {
+	ctx   context.Context
+	from  <-chan T
+	quiet time.Duration
+	clock Clock
+}
+
+func (iter *debounceIterator[T]) Next() (T, bool) {
+	var zero T
+	if iter.from == nil {
+		return zero, false
+	}
+	var item T
+	select {
+	case first, ok := <-iter.from:
+		if !ok {
+			iter.from = nil
+			return zero, false
+		}
+		item = first
+	case <-iter.ctx.Done():
+		iter.from = nil
+		return zero, false
+	}
+
+	timer := iter.clock.NewTimer(iter.quiet)
+	defer timer.Stop()
+	for {
+		select {
+		case next, ok := <-iter.from:
+			if !ok {
+				iter.from = nil
+				return item, true
+			}
+			item = next
+			// The timer can not have been received from yet, so drain it if it has just expired.
+			if !timer.Stop() {
+				<-timer.C()
+			}
+			timer.Reset(iter.quiet)
+		case <-timer.C():
+			return item, true
+		case <-iter.ctx.Done():
+			iter.from = nil
+			return zero, false
+		}
+	}
+}
+
+// Stats holds metrics about the items that passed through an iterator returned by Observe.
+type Stats struct {
+	// Count is the number of items returned.
+	Count int
+	// First and Last are the times at which the first and last item were returned.
+	First, Last time.Time
+	// Blocked is the total time spent waiting for the source to return from Next.
+	Blocked time.Duration
+}
+
+// Observe returns an iterator that returns all items from the specified iterator while recording
+// metrics about them in the returned Stats. The Stats are updated on each call to Next and should
+// not be read concurrently with it.
+func Observe[T any](from Iterator[T], opts ...TimeOption) (Iterator[T], *Stats) {
+	stats := &Stats{}
+	return &observeIterator[T]{from: from, stats: stats, clock: newTimeOptions(opts).clock}, stats
+}
+
+type observeIterator[T any] struct {
+	from  Iterator[T]
+	stats *Stats
+	clock Clock
+}
+
+func (iter *observeIterator[T]) Next() (T, bool) {
+	start := iter.clock.Now()
+	item, ok := iter.from.Next()
+	end := iter.clock.Now()
+	iter.stats.Blocked += end.Sub(start)
+	if ok {
+		if iter.stats.Count == 0 {
+			iter.stats.First = end
+		}
+		iter.stats.Last = end
+		iter.stats.Count++
+	}
+	return item, ok
+}
+
+// MemoizeTTL is like Memoize, but the cached items expire after the specified time to live. The
+// first iterator that is created after expiry starts over with a fresh source obtained from the
+// factory. Iterators that were created before the expiry keep returning the old items.
+func MemoizeTTL[T any](factory func() Iterator[T], ttl time.Duration, opts ...TimeOption) func() Iterator[T] {
+	clock := newTimeOptions(opts).clock
+	var lock sync.Mutex
+	var cache *memoizeCache[T]
+	var created time.Time
+	return func() Iterator[T] {
+		lock.Lock()
+		defer lock.Unlock()
+		if now := clock.Now(); cache == nil || now.Sub(created) >= ttl {
+			cache = &memoizeCache[T]{from: factory()}
+			created = now
+		}
+		return &memoizeIterator[T]{cache: cache}
+	}
+}
+
+// WindowByTime returns an iterator that groups items into fixed windows of time based on the
+// timestamp returned by timeFunc. Windows are aligned to multiples of the bucket duration as by
+// time.Time.Truncate. A window is returned as soon as an item belongs to a later window, windows
+// without any items are skipped.
+//
+// The items are expected to be ordered by time. An item with a timestamp before the current window
+// is added to the current window.
+func WindowByTime[T any](from Iterator[T], timeFunc func(T) time.Time, bucket time.Duration) Iterator[[]T] {
+	return &windowByTimeIterator[T]{from: from, timeFunc: timeFunc, bucket: bucket}
+}
+
+type windowByTimeIterator[T any] struct {
+	from        Iterator[T]
+	timeFunc    func(T) time.Time
+	bucket      time.Duration
+	pending     T
+	havePending bool
+}
+
+func (iter *windowByTimeIterator[T]) Next() ([]T, bool) {
+	if !iter.havePending {
+		item, ok := iter.from.Next()
+		if !ok {
+			return nil, false
+		}
+		iter.pending = item
+	}
+	window := []T{iter.pending}
+	end := iter.timeFunc(iter.pending).Truncate(iter.bucket).Add(iter.bucket)
+	iter.havePending = false
+	for item, ok := iter.from.Next(); ok; item, ok = iter.from.Next() {
+		if !iter.timeFunc(item).Before(end) {
+			iter.pending, iter.havePending = item, true
+			break
+		}
+		window = append(window, item)
+	}
+	return window, true
+}
@@ -0,0 +1,343 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchTimeout(t *testing.T) {
+	t.Run("size", func(t *testing.T) {
+		clock := newFakeClock()
+		iter := BatchTimeout(context.Background(), Range(0, 7, 1), 3, time.Hour, WithClock(clock))
+		result := ToSlice(iter)
+		if !reflect.DeepEqual(result, [][]int{{0, 1, 2}, {3, 4, 5}, {6}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if n := clock.activeTimers(); n != 0 {
+			t.Fatalf("Unexpected active timers: %v", n)
+		}
+	})
+	t.Run("slow producer", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		clock := newFakeClock()
+		proceed := make(chan struct{})
+		slow := Map(Range(0, 5, 1), func(i int) int {
+			if i == 3 {
+				// The first batch has received its items and started its timer by now.
+				clock.Advance(50 * time.Millisecond)
+				<-proceed
+			}
+			return i
+		})
+		iter := BatchTimeout(ctx, slow, 10, 50*time.Millisecond, WithClock(clock))
+		first, _ := iter.Next()
+		close(proceed)
+		result := append([][]int{first}, ToSlice(iter)...)
+		if !reflect.DeepEqual(result, [][]int{{0, 1, 2}, {3, 4}}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	t.Run("spacing", func(t *testing.T) {
+		clock := newFakeClock()
+		start := clock.Now()
+		iter := Throttle(Range(0, 3, 1), 20*time.Millisecond, WithClock(clock))
+		offsets := Map(iter, func(int) time.Duration { return clock.Now().Sub(start) })
+		result := ToSlice(offsets)
+		expect := []time.Duration{0, 20 * time.Millisecond, 40 * time.Millisecond}
+		if !reflect.DeepEqual(result, expect) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+	t.Run("slow source", func(t *testing.T) {
+		clock := newFakeClock()
+		start := clock.Now()
+		slow := Map(Range(0, 3, 1), func(i int) int {
+			clock.Sleep(30 * time.Millisecond)
+			return i
+		})
+		iter := Throttle(slow, 20*time.Millisecond, WithClock(clock))
+		offsets := Map(iter, func(int) time.Duration { return clock.Now().Sub(start) })
+		result := ToSlice(offsets)
+		expect := []time.Duration{30 * time.Millisecond, 60 * time.Millisecond, 90 * time.Millisecond}
+		if !reflect.DeepEqual(result, expect) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+	})
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("success on second attempt", func(t *testing.T) {
+		clock := newFakeClock()
+		start := clock.Now()
+		attempts := 0
+		iter, err := Retry(3, time.Second, func() (Iterator[int], error) {
+			attempts++
+			if attempts < 2 {
+				return nil, fmt.Errorf("attempt %d failed", attempts)
+			}
+			return Range(0, 3, 1), nil
+		}, WithClock(clock))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result := ToSlice(iter); !reflect.DeepEqual(result, []int{0, 1, 2}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if elapsed := clock.Now().Sub(start); elapsed != time.Second {
+			t.Fatalf("Unexpected backoff: %v", elapsed)
+		}
+	})
+	t.Run("exhausted", func(t *testing.T) {
+		attempts := 0
+		_, err := Retry(3, time.Second, func() (Iterator[int], error) {
+			attempts++
+			return nil, fmt.Errorf("attempt %d failed", attempts)
+		}, WithClock(newFakeClock()))
+		if err == nil || err.Error() != "attempt 3 failed" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("Unexpected number of attempts: %v", attempts)
+		}
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	t.Run("in time", func(t *testing.T) {
+		clock := newFakeClock()
+		iter := Timeout(Range(0, 3, 1), time.Second, WithClock(clock))
+		result := ToSlice[int](iter)
+		if !reflect.DeepEqual(result, []int{0, 1, 2}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if err := iter.Err(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if n := clock.activeTimers(); n != 0 {
+			t.Fatalf("Unexpected active timers: %v", n)
+		}
+	})
+	t.Run("blocking source", func(t *testing.T) {
+		unblock := make(chan int)
+		defer close(unblock)
+		source := Flatten(FromSlice([]Iterator[int]{Once(1), FromChannel(unblock)}))
+		iter := Timeout(source, 10*time.Millisecond)
+		result := ToSlice[int](iter)
+		if !reflect.DeepEqual(result, []int{1}) {
+			t.Fatalf("Unexpected: %v", result)
+		}
+		if err := iter.Err(); err != ErrTimeout {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDebounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := newFakeClock()
+	source := make(chan int)
+	go func() {
+		source <- 1
+		source <- 2
+		source <- 3
+		// Debounce restarts its timer for every item it receives.
+		clock.awaitArmed(3)
+		clock.Advance(time.Second)
+		source <- 4
+		close(source)
+	}()
+	iter := Debounce(ctx, FromChannel(source), time.Second, WithClock(clock))
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{3, 4}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	if n := clock.activeTimers(); n != 0 {
+		t.Fatalf("Unexpected active timers: %v", n)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	slow := Map(Range(0, 3, 1), func(i int) int {
+		clock.Sleep(time.Second)
+		return i
+	})
+	iter, stats := Observe(slow, WithClock(clock))
+	result := ToSlice(iter)
+	if !reflect.DeepEqual(result, []int{0, 1, 2}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	expect := Stats{
+		Count:   3,
+		First:   start.Add(time.Second),
+		Last:    start.Add(3 * time.Second),
+		Blocked: 3 * time.Second,
+	}
+	if *stats != expect {
+		t.Fatalf("Unexpected: %+v", *stats)
+	}
+}
+
+func TestMemoizeTTL(t *testing.T) {
+	clock := newFakeClock()
+	calls := 0
+	factory := MemoizeTTL(func() Iterator[int] {
+		calls++
+		return Range(calls*10, calls*10+3, 1)
+	}, time.Minute, WithClock(clock))
+
+	if result := ToSlice(factory()); !reflect.DeepEqual(result, []int{10, 11, 12}) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+	clock.Advance(30 * time.Second)
+	if result := ToSlice(factory()); !reflect.DeepEqual(result, []int{10, 11, 12}) {
+		t.Fatalf("Unexpected replay before expiry: %v", result)
+	}
+	clock.Advance(30 * time.Second)
+	if result := ToSlice(factory()); !reflect.DeepEqual(result, []int{20, 21, 22}) {
+		t.Fatalf("Unexpected replay after expiry: %v", result)
+	}
+	if calls != 2 {
+		t.Fatalf("Unexpected number of calls: %v", calls)
+	}
+}
+
+func TestWindowByTime(t *testing.T) {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{
+		0,
+		500 * time.Millisecond,
+		1200 * time.Millisecond,
+		1100 * time.Millisecond, // Late, but still in the current window.
+		2000 * time.Millisecond,
+		2999 * time.Millisecond,
+	}
+	iter := WindowByTime(FromSlice(offsets), func(d time.Duration) time.Time {
+		return start.Add(d)
+	}, time.Second)
+	result := ToSlice(iter)
+	expect := [][]time.Duration{
+		{0, 500 * time.Millisecond},
+		{1200 * time.Millisecond, 1100 * time.Millisecond},
+		{2000 * time.Millisecond, 2999 * time.Millisecond},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("Unexpected: %v", result)
+	}
+}
+
+// fakeClock is a Clock of which the time only advances by calling Sleep or Advance.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	armed  int
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Sleep(d time.Duration) {
+	clock.Advance(d)
+}
+
+func (clock *fakeClock) NewTimer(d time.Duration) Timer {
+	timer := &fakeTimer{clock: clock, ch: make(chan time.Time, 1)}
+	timer.Reset(d)
+	return timer
+}
+
+// Advance moves the time forward, firing all timers that expire in the meantime.
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+	pending := clock.timers[:0]
+	for _, timer := range clock.timers {
+		if timer.deadline.After(clock.now) {
+			pending = append(pending, timer)
+		} else {
+			timer.ch <- clock.now
+		}
+	}
+	clock.timers = pending
+}
+
+// activeTimers returns the number of timers that have neither fired nor been stopped.
+func (clock *fakeClock) activeTimers() int {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return len(clock.timers)
+}
+
+// awaitArmed blocks until timers have been started or reset at least n times in total.
+func (clock *fakeClock) awaitArmed(n int) {
+	for {
+		clock.mu.Lock()
+		armed := clock.armed
+		clock.mu.Unlock()
+		if armed >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (timer *fakeTimer) C() <-chan time.Time {
+	return timer.ch
+}
+
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+	return timer.remove()
+}
+
+func (timer *fakeTimer) Reset(d time.Duration) bool {
+	clock := timer.clock
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	active := timer.remove()
+	clock.armed++
+	if d <= 0 {
+		timer.ch <- clock.now
+		return active
+	}
+	timer.deadline = clock.now.Add(d)
+	clock.timers = append(clock.timers, timer)
+	return active
+}
+
+// remove unschedules the timer and reports whether it was scheduled. The clock must be locked.
+func (timer *fakeTimer) remove() bool {
+	for i, t := range timer.clock.timers {
+		if t == timer {
+			timer.clock.timers = append(timer.clock.timers[:i], timer.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}